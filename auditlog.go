@@ -0,0 +1,221 @@
+package discordbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/audit-log#audit-log-object-audit-log-structure
+type AuditLog struct {
+	Entries []AuditLogEntry `json:"audit_log_entries"`
+	// Users referenced by the entries.
+	Users []User `json:"users"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/audit-log#audit-log-entry-object-audit-log-entry-structure
+type AuditLogEntry struct {
+	Id Snowflake `json:"id"`
+	// ID of the affected guild, channel, user, role etc., depending on the action type.
+	TargetId *Snowflake       `json:"target_id"`
+	Changes  []AuditLogChange `json:"changes,omitempty"`
+	// User that made the change.
+	UserId     *Snowflake `json:"user_id"`
+	ActionType int        `json:"action_type"`
+	// Extra details for some action types.
+	Options *AuditLogOptions `json:"options,omitempty"`
+	Reason  *string          `json:"reason,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/audit-log#audit-log-entry-object-audit-log-events
+const (
+	AuditLogGuildUpdate            = 1
+	AuditLogChannelCreate          = 10
+	AuditLogChannelUpdate          = 11
+	AuditLogChannelDelete          = 12
+	AuditLogChannelOverwriteCreate = 13
+	AuditLogChannelOverwriteUpdate = 14
+	AuditLogChannelOverwriteDelete = 15
+	AuditLogMemberKick             = 20
+	AuditLogMemberPrune            = 21
+	AuditLogMemberBanAdd           = 22
+	AuditLogMemberBanRemove        = 23
+	AuditLogMemberUpdate           = 24
+	AuditLogMemberRoleUpdate       = 25
+	AuditLogMemberMove             = 26
+	AuditLogMemberDisconnect       = 27
+	AuditLogBotAdd                 = 28
+	AuditLogRoleCreate             = 30
+	AuditLogRoleUpdate             = 31
+	AuditLogRoleDelete             = 32
+	AuditLogInviteCreate           = 40
+	AuditLogInviteUpdate           = 41
+	AuditLogInviteDelete           = 42
+	AuditLogWebhookCreate          = 50
+	AuditLogWebhookUpdate          = 51
+	AuditLogWebhookDelete          = 52
+	AuditLogEmojiCreate            = 60
+	AuditLogEmojiUpdate            = 61
+	AuditLogEmojiDelete            = 62
+	AuditLogMessageDelete          = 72
+	AuditLogMessageBulkDelete      = 73
+	AuditLogMessagePin             = 74
+	AuditLogMessageUnpin           = 75
+	AuditLogIntegrationCreate      = 80
+	AuditLogIntegrationUpdate      = 81
+	AuditLogIntegrationDelete      = 82
+	AuditLogStickerCreate          = 90
+	AuditLogStickerUpdate          = 91
+	AuditLogStickerDelete          = 92
+)
+
+// Numbers are sent as strings.
+// Reference: https://discordapp.com/developers/docs/resources/audit-log#audit-log-entry-object-optional-audit-entry-info
+type AuditLogOptions struct {
+	// Member prune.
+	DeleteMemberDays string `json:"delete_member_days,omitempty"`
+	MembersRemoved   string `json:"members_removed,omitempty"`
+	// Member move, message delete, pin and unpin.
+	ChannelId *Snowflake `json:"channel_id,omitempty"`
+	// Message pin and unpin.
+	MessageId *Snowflake `json:"message_id,omitempty"`
+	// Message delete, bulk delete, member move and disconnect.
+	Count string `json:"count,omitempty"`
+	// Channel overwrites: the overwritten role or user, its type and, for roles, the role name.
+	Id       *Snowflake `json:"id,omitempty"`
+	Type     string     `json:"type,omitempty"`
+	RoleName string     `json:"role_name,omitempty"`
+}
+
+// A changed field. The values are decoded with Values, which picks the type from the key.
+// Reference: https://discordapp.com/developers/docs/resources/audit-log#audit-log-change-object
+type AuditLogChange struct {
+	Key string `json:"key"`
+	// Not set when the object was created.
+	OldValue json.RawMessage `json:"old_value,omitempty"`
+	// Not set when the object was deleted.
+	NewValue json.RawMessage `json:"new_value,omitempty"`
+}
+
+// Types of the change values, by key. Keys not listed are decoded as generic JSON values.
+// Reference: https://discordapp.com/developers/docs/resources/audit-log#audit-log-change-object-audit-log-change-key
+var auditLogChangeTypes = map[string]func() interface{}{
+	"name":                          func() interface{} { return new(string) },
+	"description":                   func() interface{} { return new(string) },
+	"icon_hash":                     func() interface{} { return new(string) },
+	"avatar_hash":                   func() interface{} { return new(string) },
+	"splash_hash":                   func() interface{} { return new(string) },
+	"banner_hash":                   func() interface{} { return new(string) },
+	"region":                        func() interface{} { return new(string) },
+	"vanity_url_code":               func() interface{} { return new(string) },
+	"topic":                         func() interface{} { return new(string) },
+	"nick":                          func() interface{} { return new(string) },
+	"code":                          func() interface{} { return new(string) },
+	"tags":                          func() interface{} { return new(string) },
+	"owner_id":                      func() interface{} { return new(Snowflake) },
+	"afk_channel_id":                func() interface{} { return new(Snowflake) },
+	"widget_channel_id":             func() interface{} { return new(Snowflake) },
+	"system_channel_id":             func() interface{} { return new(Snowflake) },
+	"channel_id":                    func() interface{} { return new(Snowflake) },
+	"inviter_id":                    func() interface{} { return new(Snowflake) },
+	"application_id":                func() interface{} { return new(Snowflake) },
+	"id":                            func() interface{} { return new(Snowflake) },
+	"afk_timeout":                   func() interface{} { return new(int) },
+	"mfa_level":                     func() interface{} { return new(int) },
+	"verification_level":            func() interface{} { return new(int) },
+	"explicit_content_filter":       func() interface{} { return new(int) },
+	"default_message_notifications": func() interface{} { return new(int) },
+	"prune_delete_days":             func() interface{} { return new(int) },
+	"position":                      func() interface{} { return new(int) },
+	"bitrate":                       func() interface{} { return new(int) },
+	"user_limit":                    func() interface{} { return new(int) },
+	"rate_limit_per_user":           func() interface{} { return new(int) },
+	"max_uses":                      func() interface{} { return new(int) },
+	"uses":                          func() interface{} { return new(int) },
+	"max_age":                       func() interface{} { return new(int) },
+	"color":                         func() interface{} { return new(Color) },
+	"permissions":                   func() interface{} { return new(Permissions) },
+	"allow":                         func() interface{} { return new(Permissions) },
+	"deny":                          func() interface{} { return new(Permissions) },
+	"widget_enabled":                func() interface{} { return new(bool) },
+	"nsfw":                          func() interface{} { return new(bool) },
+	"hoist":                         func() interface{} { return new(bool) },
+	"mentionable":                   func() interface{} { return new(bool) },
+	"temporary":                     func() interface{} { return new(bool) },
+	"deaf":                          func() interface{} { return new(bool) },
+	"mute":                          func() interface{} { return new(bool) },
+	"communication_disabled_until":  func() interface{} { return new(Timestamp) },
+	"permission_overwrites":         func() interface{} { return new([]Overwrite) },
+	// Partial roles, with only the ID and name, added to or removed from a member.
+	"$add":    func() interface{} { return new([]Role) },
+	"$remove": func() interface{} { return new([]Role) },
+}
+
+// Decodes the old and new values into the type for the key, e.g. *string for "name" or
+// *Permissions for "permissions". Values that weren't set are returned as nil.
+func (c AuditLogChange) Values() (oldValue interface{}, newValue interface{}, err error) {
+	if oldValue, err = c.decode(c.OldValue); err != nil {
+		return nil, nil, fmt.Errorf("invalid old value for [%s]: %v", c.Key, err)
+	}
+
+	if newValue, err = c.decode(c.NewValue); err != nil {
+		return nil, nil, fmt.Errorf("invalid new value for [%s]: %v", c.Key, err)
+	}
+	return
+}
+
+func (c AuditLogChange) decode(data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var value interface{}
+	if newValue, ok := auditLogChangeTypes[c.Key]; ok {
+		value = newValue()
+	} else {
+		value = new(interface{})
+	}
+
+	return value, json.Unmarshal(data, value)
+}
+
+// Filters for GetGuildAuditLog. Zero fields are unset.
+type AuditLogQuery struct {
+	// Only entries for changes made by this user.
+	UserId Snowflake
+	// Only entries of this AuditLog type.
+	ActionType int
+	// Only entries older than this entry ID. Set to the last entry of the previous page to get the next one.
+	Before Snowflake
+	// 1-100, defaults to 50.
+	Limit int
+}
+
+// Gets audit log entries, newest first.
+// Reference: https://discordapp.com/developers/docs/resources/audit-log#get-guild-audit-log
+func (client *DiscordClient) GetGuildAuditLog(guildId Snowflake, query AuditLogQuery) (auditLog AuditLog, err error) {
+	values := url.Values{}
+	if query.UserId != 0 {
+		values.Set("user_id", query.UserId.String())
+	}
+	if query.ActionType != 0 {
+		values.Set("action_type", strconv.Itoa(query.ActionType))
+	}
+	if query.Before != 0 {
+		values.Set("before", query.Before.String())
+	}
+	if query.Limit != 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/audit-logs", guildsEndpoint, guildId),
+		query:    values,
+		result:   &auditLog,
+	})
+	return
+}
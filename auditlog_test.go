@@ -0,0 +1,85 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+const auditLogPayload = `{
+	"audit_log_entries": [{
+		"id": "3", "target_id": "4", "user_id": "5", "action_type": 25, "reason": "promotion",
+		"changes": [{"key": "$add", "new_value": [{"id": "6", "name": "mods"}]}]
+	}, {
+		"id": "2", "target_id": "6", "user_id": "5", "action_type": 31,
+		"changes": [
+			{"key": "permissions", "old_value": "0", "new_value": "8192"},
+			{"key": "name", "old_value": "helpers", "new_value": "mods"},
+			{"key": "unknown_key", "new_value": {"a": 1}}
+		]
+	}, {
+		"id": "1", "target_id": null, "user_id": "5", "action_type": 72,
+		"options": {"channel_id": "7", "count": "3"}
+	}],
+	"users": [{"id": "5", "username": "admin"}]
+}`
+
+func TestGetGuildAuditLog(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, json.RawMessage(auditLogPayload)
+	})
+
+	client := api.client(t)
+	auditLog, err := client.GetGuildAuditLog(1, discordbot.AuditLogQuery{UserId: 5, ActionType: discordbot.AuditLogRoleUpdate, Limit: 10})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request := api.single(t); request.Path != "/guilds/1/audit-logs" || request.Query != "action_type=31&limit=10&user_id=5" {
+		t.Errorf("unexpected request %s?%s", request.Path, request.Query)
+	}
+
+	if len(auditLog.Entries) != 3 || len(auditLog.Users) != 1 {
+		t.Fatalf("unexpected audit log %+v", auditLog)
+	}
+
+	_, added, err := auditLog.Entries[0].Changes[0].Values()
+	if roles, ok := added.(*[]discordbot.Role); err != nil || !ok || len(*roles) != 1 || (*roles)[0].Name != "mods" {
+		t.Errorf("unexpected added roles %#v: %v", added, err)
+	}
+
+	oldValue, newValue, err := auditLog.Entries[1].Changes[0].Values()
+	if err != nil || *oldValue.(*discordbot.Permissions) != 0 || *newValue.(*discordbot.Permissions) != discordbot.PermissionManageMessages {
+		t.Errorf("unexpected permission change %v to %v: %v", oldValue, newValue, err)
+	}
+
+	if oldValue, _, _ := auditLog.Entries[1].Changes[1].Values(); *oldValue.(*string) != "helpers" {
+		t.Errorf("unexpected old name %v", oldValue)
+	}
+
+	if _, newValue, err := auditLog.Entries[1].Changes[2].Values(); err != nil || newValue == nil {
+		t.Errorf("unknown keys should decode generically, got %v: %v", newValue, err)
+	}
+
+	if entry := auditLog.Entries[2]; entry.TargetId != nil || entry.Options == nil || *entry.Options.ChannelId != 7 {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+}
+
+func TestAuditLogReasonEncoding(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNoContent, nil
+	})
+
+	client := api.client(t)
+	if err := client.RemoveGuildMember(1, 2, "spam / raid ✓\nagain"); err != nil {
+		t.Fatal(err)
+	}
+
+	if reason := api.single(t).Header.Get("X-Audit-Log-Reason"); reason != "spam%20%2F%20raid%20%E2%9C%93%0Aagain" {
+		t.Errorf("unexpected reason header [%s]", reason)
+	}
+}
@@ -0,0 +1,143 @@
+package discordbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#ban-object-ban-structure
+type Ban struct {
+	Reason *string `json:"reason"`
+	User   User    `json:"user"`
+}
+
+// Page of a guild's bans. Zero fields are unset.
+type GuildBansQuery struct {
+	// Only bans of users with a smaller ID.
+	Before Snowflake
+	// Only bans of users with a greater ID. Set to the last user of the previous page to get the next one.
+	After Snowflake
+	// 1-1000, defaults to 1000.
+	Limit int
+}
+
+// Gets a page of a guild's bans in user ID order.
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-bans
+func (client *DiscordClient) GetGuildBans(guildId Snowflake, query GuildBansQuery) (bans []Ban, err error) {
+	values := url.Values{}
+	if query.Before != 0 {
+		values.Set("before", query.Before.String())
+	}
+	if query.After != 0 {
+		values.Set("after", query.After.String())
+	}
+	if query.Limit != 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/bans", guildsEndpoint, guildId),
+		query:    values,
+		result:   &bans,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-ban
+func (client *DiscordClient) GetGuildBan(guildId Snowflake, userId Snowflake) (ban Ban, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/bans/%s", guildsEndpoint, guildId, userId),
+		result:   &ban,
+	})
+	return
+}
+
+// Messages sent up to 7 days before a ban can be deleted with it.
+const maxBanDeleteMessageSeconds = 7 * 24 * 60 * 60
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#create-guild-ban-json-params
+type createGuildBan struct {
+	DeleteMessageSeconds int `json:"delete_message_seconds,omitempty"`
+}
+
+// Bans a user, deleting the messages they sent in the last deleteMessageSeconds, up to 7 days.
+// Reference: https://discordapp.com/developers/docs/resources/guild#create-guild-ban
+func (client *DiscordClient) CreateGuildBan(guildId Snowflake, userId Snowflake, deleteMessageSeconds int, reason string) error {
+	if deleteMessageSeconds < 0 || deleteMessageSeconds > maxBanDeleteMessageSeconds {
+		return fmt.Errorf("delete message seconds [%d] must be between 0 and %d", deleteMessageSeconds, maxBanDeleteMessageSeconds)
+	}
+
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodPut,
+		endpoint: fmt.Sprintf("%s/%s/bans/%s", guildsEndpoint, guildId, userId),
+		body:     createGuildBan{DeleteMessageSeconds: deleteMessageSeconds},
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#remove-guild-ban
+func (client *DiscordClient) RemoveGuildBan(guildId Snowflake, userId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/bans/%s", guildsEndpoint, guildId, userId),
+		reason:   reason,
+	})
+}
+
+const maxBulkBanUsers = 200
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#bulk-guild-ban-json-params
+type bulkGuildBan struct {
+	UserIds              []Snowflake `json:"user_ids"`
+	DeleteMessageSeconds int         `json:"delete_message_seconds,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#bulk-guild-ban-bulk-ban-response
+type BulkBanResult struct {
+	BannedUsers []Snowflake `json:"banned_users"`
+	// Users that couldn't be banned, e.g. because they were already banned.
+	FailedUsers []Snowflake `json:"failed_users"`
+}
+
+// Bans any number of users, in batches of up to 200, deleting the messages they sent in the last
+// deleteMessageSeconds. On error, the result holds the batches that completed.
+// Reference: https://discordapp.com/developers/docs/resources/guild#bulk-guild-ban
+func (client *DiscordClient) BulkGuildBan(
+	guildId Snowflake, userIds []Snowflake, deleteMessageSeconds int, reason string,
+) (result BulkBanResult, err error) {
+
+	if deleteMessageSeconds < 0 || deleteMessageSeconds > maxBanDeleteMessageSeconds {
+		return result, fmt.Errorf("delete message seconds [%d] must be between 0 and %d", deleteMessageSeconds, maxBanDeleteMessageSeconds)
+	}
+
+	for len(userIds) > 0 {
+		batch := userIds
+		if len(batch) > maxBulkBanUsers {
+			batch = batch[:maxBulkBanUsers]
+		}
+		userIds = userIds[len(batch):]
+
+		batchResult := BulkBanResult{}
+		err = client.do(context.Background(), restRequest{
+			method:   http.MethodPost,
+			endpoint: fmt.Sprintf("%s/%s/bulk-ban", guildsEndpoint, guildId),
+			body:     bulkGuildBan{UserIds: batch, DeleteMessageSeconds: deleteMessageSeconds},
+			reason:   reason,
+			result:   &batchResult,
+		})
+
+		if err != nil {
+			return result, fmt.Errorf("failed to ban users: %w", err)
+		}
+
+		result.BannedUsers = append(result.BannedUsers, batchResult.BannedUsers...)
+		result.FailedUsers = append(result.FailedUsers, batchResult.FailedUsers...)
+	}
+
+	return
+}
@@ -0,0 +1,69 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestCreateGuildBan(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNoContent, nil
+	})
+
+	client := api.client(t)
+	if err := client.CreateGuildBan(1, 2, 3600, "raid"); err != nil {
+		t.Fatal(err)
+	}
+
+	request := api.single(t)
+	if request.Method != http.MethodPut || request.Path != "/guilds/1/bans/2" ||
+		string(request.Body) != `{"delete_message_seconds":3600}` || request.Header.Get("X-Audit-Log-Reason") != "raid" {
+		t.Errorf("unexpected request %s %s %s", request.Method, request.Path, request.Body)
+	}
+
+	if err := client.CreateGuildBan(1, 2, 8*24*60*60, ""); err == nil {
+		t.Error("expected an error deleting more than 7 days of messages")
+	}
+}
+
+func TestBulkGuildBan(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		body := struct {
+			UserIds []discordbot.Snowflake `json:"user_ids"`
+		}{}
+		json.Unmarshal(request.Body, &body)
+		return http.StatusOK, map[string]interface{}{"banned_users": body.UserIds[1:], "failed_users": body.UserIds[:1]}
+	})
+
+	userIds := []discordbot.Snowflake{}
+	for i := 1; i <= 250; i++ {
+		userIds = append(userIds, discordbot.Snowflake(i))
+	}
+
+	client := api.client(t)
+	result, err := client.BulkGuildBan(1, userIds, 0, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.BannedUsers) != 248 || len(result.FailedUsers) != 2 || result.FailedUsers[1] != 201 {
+		t.Errorf("unexpected result with %d banned and failed %v", len(result.BannedUsers), result.FailedUsers)
+	}
+	if requests := api.received(); len(requests) != 2 {
+		t.Errorf("expected 2 batches, got %d", len(requests))
+	}
+}
+
+func TestGuildBanEvent(t *testing.T) {
+	ban := discordbot.GuildBanUpdate{}
+	if err := json.Unmarshal([]byte(`{"guild_id": "1", "user": {"id": "2", "username": "spammer"}}`), &ban); err != nil {
+		t.Fatal(err)
+	}
+
+	if ban.GuildId != 1 || ban.User.Username != "spammer" {
+		t.Errorf("unexpected ban %+v", ban)
+	}
+}
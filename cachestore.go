@@ -0,0 +1,160 @@
+package discordbot
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// Storage used by a State. Values are JSON encoded and grouped into buckets, one per kind of
+// data. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Returns ok false if the key is not in the bucket.
+	Get(bucket string, key string) (value []byte, ok bool, err error)
+	Put(bucket string, key string, value []byte) error
+	// Deleting a missing key is not an error.
+	Delete(bucket string, key string) error
+	// Calls fn for every entry in the bucket whose key starts with prefix. fn must not modify the store.
+	Scan(bucket string, prefix string, fn func(key string, value []byte)) error
+	// Applies the writes in order, in a single transaction if the store supports them.
+	Write(writes []CacheWrite) error
+	Close() error
+}
+
+// Change applied by CacheStore.Write. A nil Value deletes the key.
+type CacheWrite struct {
+	Bucket string
+	Key    string
+	Value  []byte
+}
+
+// Number of entries kept by the default in-memory store.
+const DefaultCacheSize = 100000
+
+// In-memory CacheStore that evicts the least recently used entry once full.
+type MemoryCacheStore struct {
+	maxEntries int
+
+	mutex sync.Mutex
+	// Most recently used first.
+	order   *list.List
+	buckets map[string]map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	bucket string
+	key    string
+	value  []byte
+}
+
+// Creates a store holding at most maxEntries entries across all buckets. 0 or less means no limit.
+func NewMemoryCacheStore(maxEntries int) *MemoryCacheStore {
+	return &MemoryCacheStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		buckets:    make(map[string]map[string]*list.Element),
+	}
+}
+
+func (m *MemoryCacheStore) Get(bucket string, key string) (value []byte, ok bool, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	element, ok := m.buckets[bucket][key]
+	if !ok {
+		return
+	}
+
+	m.order.MoveToFront(element)
+	return element.Value.(*memoryCacheEntry).value, true, nil
+}
+
+func (m *MemoryCacheStore) Put(bucket string, key string, value []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.put(bucket, key, value)
+	return nil
+}
+
+func (m *MemoryCacheStore) Delete(bucket string, key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.delete(bucket, key)
+	return nil
+}
+
+func (m *MemoryCacheStore) Write(writes []CacheWrite) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, write := range writes {
+		if write.Value == nil {
+			m.delete(write.Bucket, write.Key)
+		} else {
+			m.put(write.Bucket, write.Key, write.Value)
+		}
+	}
+	return nil
+}
+
+// Must be called with the mutex held.
+func (m *MemoryCacheStore) put(bucket string, key string, value []byte) {
+	if element, ok := m.buckets[bucket][key]; ok {
+		element.Value.(*memoryCacheEntry).value = value
+		m.order.MoveToFront(element)
+		return
+	}
+
+	if m.buckets[bucket] == nil {
+		m.buckets[bucket] = make(map[string]*list.Element)
+	}
+	m.buckets[bucket][key] = m.order.PushFront(&memoryCacheEntry{bucket: bucket, key: key, value: value})
+
+	for m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		m.remove(m.order.Back())
+	}
+}
+
+// Must be called with the mutex held.
+func (m *MemoryCacheStore) delete(bucket string, key string) {
+	if element, ok := m.buckets[bucket][key]; ok {
+		m.remove(element)
+	}
+}
+
+// Scanning doesn't count as use for eviction.
+func (m *MemoryCacheStore) Scan(bucket string, prefix string, fn func(key string, value []byte)) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key, element := range m.buckets[bucket] {
+		if strings.HasPrefix(key, prefix) {
+			fn(key, element.Value.(*memoryCacheEntry).value)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryCacheStore) Close() error {
+	return nil
+}
+
+// Number of entries in the store.
+func (m *MemoryCacheStore) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.order.Len()
+}
+
+// Must be called with the mutex held.
+func (m *MemoryCacheStore) remove(element *list.Element) {
+	entry := m.order.Remove(element).(*memoryCacheEntry)
+	delete(m.buckets[entry.bucket], entry.key)
+
+	if len(m.buckets[entry.bucket]) == 0 {
+		delete(m.buckets, entry.bucket)
+	}
+}
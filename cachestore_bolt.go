@@ -0,0 +1,107 @@
+package discordbot
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// CacheStore backed by a bbolt database file, so that a restarted process can warm up its
+// State from the previous run. Entries are never evicted.
+type BoltCacheStore struct {
+	db *bolt.DB
+}
+
+// Opens or creates the database file. Only one process can have the file open at a time.
+func OpenBoltCacheStore(path string) (*BoltCacheStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Duration(5) * time.Second})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache file [%s]: %v", path, err)
+	}
+
+	return &BoltCacheStore{db: db}, nil
+}
+
+func (b *BoltCacheStore) Get(bucket string, key string) (value []byte, ok bool, err error) {
+	err = b.db.View(func(tx *bolt.Tx) error {
+		if bb := tx.Bucket([]byte(bucket)); bb != nil {
+			// Values are only valid during the transaction.
+			if stored := bb.Get([]byte(key)); stored != nil {
+				value = append([]byte(nil), stored...)
+				ok = true
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (b *BoltCacheStore) Put(bucket string, key string, value []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bb, err := tx.CreateBucketIfNotExists([]byte(bucket))
+
+		if err != nil {
+			return err
+		}
+
+		return bb.Put([]byte(key), value)
+	})
+}
+
+func (b *BoltCacheStore) Delete(bucket string, key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if bb := tx.Bucket([]byte(bucket)); bb != nil {
+			return bb.Delete([]byte(key))
+		}
+		return nil
+	})
+}
+
+// All writes are committed together, with a single sync to disk.
+func (b *BoltCacheStore) Write(writes []CacheWrite) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		for _, write := range writes {
+			if write.Value == nil {
+				if bb := tx.Bucket([]byte(write.Bucket)); bb != nil {
+					if err := bb.Delete([]byte(write.Key)); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			bb, err := tx.CreateBucketIfNotExists([]byte(write.Bucket))
+
+			if err != nil {
+				return err
+			}
+
+			if err = bb.Put([]byte(write.Key), write.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BoltCacheStore) Scan(bucket string, prefix string, fn func(key string, value []byte)) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		bb := tx.Bucket([]byte(bucket))
+		if bb == nil {
+			return nil
+		}
+
+		cursor := bb.Cursor()
+		for key, value := cursor.Seek([]byte(prefix)); key != nil && bytes.HasPrefix(key, []byte(prefix)); key, value = cursor.Next() {
+			fn(string(key), append([]byte(nil), value...))
+		}
+		return nil
+	})
+}
+
+func (b *BoltCacheStore) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,236 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestMemoryCacheStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := discordbot.NewMemoryCacheStore(2)
+
+	store.Put("guilds", "1", []byte("one"))
+	store.Put("channels", "2", []byte("two"))
+
+	// Using the first entry makes the second the least recently used.
+	if value, ok, _ := store.Get("guilds", "1"); !ok || string(value) != "one" {
+		t.Fatalf("unexpected value [%s]", value)
+	}
+
+	store.Put("guilds", "3", []byte("three"))
+
+	if _, ok, _ := store.Get("channels", "2"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, ok, _ := store.Get("guilds", "1"); !ok {
+		t.Error("recently used entry was evicted")
+	}
+	if store.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", store.Len())
+	}
+
+	keys := []string{}
+	store.Scan("guilds", "", func(key string, value []byte) {
+		keys = append(keys, key)
+	})
+	if len(keys) != 2 {
+		t.Errorf("unexpected scanned keys %v", keys)
+	}
+}
+
+func TestBoltCacheStoreWarmsUpState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	store, err := discordbot.OpenBoltCacheStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := discordbot.DefaultStateOptions()
+	options.Store = store
+	state := discordbot.NewState(options)
+
+	fake := newFakeGateway(t)
+	gateway := &discordbot.DiscordGateway{
+		GatewayInfo: discordbot.GatewayInfo{Url: fake.url()},
+		StateCache:  state,
+	}
+	if err := gateway.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	fake.conn = <-fake.conns
+
+	guildCreate := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(guildCreatePayload), &guildCreate); err != nil {
+		t.Fatal(err)
+	}
+	dispatchToState(t, fake, gateway,
+		discordbot.EventGuildCreate, guildCreate,
+		discordbot.EventMessageCreate, map[string]interface{}{"id": "10", "channel_id": "41771983423143937", "content": "a"},
+	)
+
+	if err := state.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new process reopening the file sees the cached data without any events.
+	store, err = discordbot.OpenBoltCacheStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.Store = store
+	state = discordbot.NewState(options)
+	defer state.Close()
+
+	const guildId = 41771983423143937
+	guild, ok := state.Guild(guildId)
+	if !ok || guild.Name != "Discord Developers" || len(guild.Roles) != 1 || len(*guild.Channels) != 1 {
+		t.Fatalf("guild not restored: %+v", guild)
+	}
+
+	if _, ok := state.Member(guildId, 80351110224678912); !ok {
+		t.Error("member not restored")
+	}
+
+	if message, ok := state.Message(guildId, 10); !ok || message.Content != "a" {
+		t.Errorf("message not restored: %+v", message)
+	}
+
+	if guildIds := state.GuildIds(); len(guildIds) != 1 || guildIds[0] != guildId {
+		t.Errorf("unexpected guild IDs %v", guildIds)
+	}
+}
+
+// Store that counts the individual writes and transactions made by a State.
+type countingCacheStore struct {
+	*discordbot.MemoryCacheStore
+	mutex   sync.Mutex
+	singles int
+	batches int
+}
+
+func (c *countingCacheStore) Put(bucket string, key string, value []byte) error {
+	c.mutex.Lock()
+	c.singles++
+	c.mutex.Unlock()
+	return c.MemoryCacheStore.Put(bucket, key, value)
+}
+
+func (c *countingCacheStore) Delete(bucket string, key string) error {
+	c.mutex.Lock()
+	c.singles++
+	c.mutex.Unlock()
+	return c.MemoryCacheStore.Delete(bucket, key)
+}
+
+func (c *countingCacheStore) Write(writes []discordbot.CacheWrite) error {
+	c.mutex.Lock()
+	c.batches++
+	c.mutex.Unlock()
+	return c.MemoryCacheStore.Write(writes)
+}
+
+func TestStateWritesEachEventInOneTransaction(t *testing.T) {
+	store := &countingCacheStore{MemoryCacheStore: discordbot.NewMemoryCacheStore(0)}
+
+	options := discordbot.DefaultStateOptions()
+	options.Store = store
+	state := discordbot.NewState(options)
+
+	fake := newFakeGateway(t)
+	gateway := &discordbot.DiscordGateway{
+		GatewayInfo: discordbot.GatewayInfo{Url: fake.url()},
+		StateCache:  state,
+	}
+	if err := gateway.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	fake.conn = <-fake.conns
+
+	guildCreate := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(guildCreatePayload), &guildCreate); err != nil {
+		t.Fatal(err)
+	}
+	dispatchToState(t, fake, gateway, discordbot.EventGuildCreate, guildCreate)
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.singles != 0 || store.batches != 1 {
+		t.Errorf("expected a single transaction, got %d writes and %d transactions", store.singles, store.batches)
+	}
+}
+
+// Holds every transaction until released.
+type blockingCacheStore struct {
+	countingCacheStore
+	writing chan bool
+	release chan struct{}
+}
+
+func (b *blockingCacheStore) Write(writes []discordbot.CacheWrite) error {
+	select {
+	case b.writing <- true:
+	default:
+	}
+
+	<-b.release
+	return b.countingCacheStore.Write(writes)
+}
+
+func TestStateIngestsOffTheReadLoopInBatches(t *testing.T) {
+	store := &blockingCacheStore{
+		countingCacheStore: countingCacheStore{MemoryCacheStore: discordbot.NewMemoryCacheStore(0)},
+		writing:            make(chan bool, 1),
+		release:            make(chan struct{}),
+	}
+
+	options := discordbot.DefaultStateOptions()
+	options.Store = store
+	state := discordbot.NewState(options)
+
+	fake := newFakeGateway(t)
+	gateway := &discordbot.DiscordGateway{
+		GatewayInfo: discordbot.GatewayInfo{Url: fake.url()},
+		StateCache:  state,
+	}
+	if err := gateway.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer gateway.Close()
+	fake.conn = <-fake.conns
+
+	fake.dispatch(t, discordbot.EventMessageCreate, map[string]interface{}{"id": "10", "channel_id": "1", "content": "a"})
+	select {
+	case <-store.writing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("first event was not written")
+	}
+
+	// Events keep being read while the store is stuck on the first one.
+	fake.dispatch(t, discordbot.EventMessageCreate, map[string]interface{}{"id": "11", "channel_id": "1", "content": "b"})
+	fake.dispatch(t, discordbot.EventMessageUpdate, map[string]interface{}{"id": "11", "channel_id": "1", "content": "edited"})
+	fake.dispatch(t, discordbot.EventMessageDelete, map[string]interface{}{"id": "10", "channel_id": "1"})
+	if sequence := fake.heartbeat(t); sequence != 4 {
+		t.Errorf("heartbeat sent with sequence %d while the store was busy", sequence)
+	}
+
+	close(store.release)
+	dispatchToState(t, fake, gateway)
+
+	messages := state.Messages(1)
+	if len(messages) != 1 || messages[0].Id != 11 || messages[0].Content != "edited" {
+		t.Errorf("events not applied in order: %+v", messages)
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.batches != 2 {
+		t.Errorf("expected the queued events in one transaction, got %d transactions", store.batches)
+	}
+}
@@ -0,0 +1,45 @@
+package discordbot
+
+import (
+	"strconv"
+	"strings"
+)
+
+const cdnUrl = "https://cdn.discordapp.com"
+
+// File format of a CDN image.
+// Reference: https://discordapp.com/developers/docs/reference#image-formatting-image-formats
+type ImageFormat string
+
+const (
+	// PNG, or GIF for animated images.
+	ImageFormatDefault ImageFormat = ""
+	ImageFormatPng     ImageFormat = "png"
+	ImageFormatJpeg    ImageFormat = "jpg"
+	ImageFormatWebp    ImageFormat = "webp"
+	// Only available for animated images; others fall back to PNG.
+	ImageFormatGif ImageFormat = "gif"
+)
+
+// Hashes of animated images start with this prefix.
+const animatedImagePrefix = "a_"
+
+// URL of an image stored under the given path and hash. Size is a power of 2 from 16 to 4096,
+// or 0 for the original size.
+// Reference: https://discordapp.com/developers/docs/reference#image-formatting
+func cdnImageUrl(path string, hash string, size int, format ImageFormat) string {
+	animated := strings.HasPrefix(hash, animatedImagePrefix)
+
+	switch {
+	case format == ImageFormatDefault && animated:
+		format = ImageFormatGif
+	case format == ImageFormatDefault, format == ImageFormatGif && !animated:
+		format = ImageFormatPng
+	}
+
+	imageUrl := cdnUrl + path + "/" + hash + "." + string(format)
+	if size > 0 {
+		imageUrl += "?size=" + strconv.Itoa(size)
+	}
+	return imageUrl
+}
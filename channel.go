@@ -1,45 +1,53 @@
 package discordbot
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
+	"time"
 )
 
 // Reference:
 // https://discordapp.com/developers/docs/resources/channel#channel-object-channel-structure
 type Channel struct {
-	Id                   string       `json:"id"`
+	Id                   Snowflake    `json:"id"`
 	Type                 int          `json:"type"`
-	GuildId              *string      `json:"guild_id,omitempty"`
+	GuildId              *Snowflake   `json:"guild_id,omitempty"`
 	Position             *int         `json:"position,omitempty"`
 	PermissionOverwrites *[]Overwrite `json:"permission_overwrites,omitempty"`
 	Name                 *string      `json:"name,omitempty"`
 	Topic                *string      `json:"topic,omitempty"`
-	Nsfw                 *bool        `json:"nswf,omitempty"`
-	LastMessageId        *string      `json:"last_message_id,omitempty"`
+	Nsfw                 *bool        `json:"nsfw,omitempty"`
+	LastMessageId        *Snowflake   `json:"last_message_id,omitempty"`
 	Bitrate              *int         `json:"bitrate,omitempty"`
 	UserLimit            *int         `json:"user_limit,omitempty"`
 	Recipients           *[]User      `json:"recipients,omitempty"`
 	Icon                 *string      `json:"icon,omitempty"`
-	OwnerId              *string      `json:"owner_id,omitempty"`
-	ApplicationId        *string      `json:"application_id,omitempty"`
-	ParentId             *string      `json:"parent_id,omitempty"`
-	LastPinTimestamp     *string      `json:"last_pin_timestamp,omitempty"`
+	OwnerId              *Snowflake   `json:"owner_id,omitempty"`
+	ApplicationId        *Snowflake   `json:"application_id,omitempty"`
+	ParentId             *Snowflake   `json:"parent_id,omitempty"`
+	LastPinTimestamp     *Timestamp   `json:"last_pin_timestamp,omitempty"`
+	// Seconds a user has to wait between messages. Bots and users with manage permissions are exempt.
+	RateLimitPerUser *int `json:"rate_limit_per_user,omitempty"`
 }
 
 // Reference:
 // https://discordapp.com/developers/docs/resources/channel#overwrite-object-overwrite-structure
 type Overwrite struct {
-	Id    string `json:"id"`
-	Type  string `json:"type"`
-	Allow int    `json:"allow"`
-	Deny  int    `json:"deny"`
+	// Role or user ID, depending on the type.
+	Id    Snowflake   `json:"id"`
+	Type  string      `json:"type"`
+	Allow Permissions `json:"allow"`
+	Deny  Permissions `json:"deny"`
 }
 
+// Overwrite types
+const (
+	OverwriteTypeRole   = "role"
+	OverwriteTypeMember = "member"
+)
+
 // Reference
 // https://discordapp.com/developers/docs/resources/channel#channel-object-channel-types
 const (
@@ -50,27 +58,33 @@ const (
 	ChannelTypeGuildCategory = 4
 )
 
+// Reference:
+// https://discordapp.com/developers/docs/resources/channel#message-object-message-structure
 type Message struct {
-	Id              string  `json:"id"`
-	ChannelId       string  `json:"channel_id"`
-	Author          User    `json:"author*,omitempty"`
-	Content         string  `json:"content"`
-	Timestamp       string  `json:"timestamp"`
-	EditedTimestamp *string `json:"edited_timestamp,omitempty"`
-	Tts             bool    `json:"tts"`
-	MentionEveryone bool    `json:"mention_everyone"`
-	Mentions        []User  `json:"mentions"`
+	Id        Snowflake  `json:"id"`
+	ChannelId Snowflake  `json:"channel_id"`
+	GuildId   *Snowflake `json:"guild_id,omitempty"`
+	// Not a real user for webhook messages.
+	Author          User       `json:"author"`
+	Content         string     `json:"content"`
+	Timestamp       Timestamp  `json:"timestamp"`
+	EditedTimestamp *Timestamp `json:"edited_timestamp,omitempty"`
+	Tts             bool       `json:"tts"`
+	MentionEveryone bool       `json:"mention_everyone"`
+	Mentions        []User     `json:"mentions"`
 	// Mention role IDs
-	MentionRoles []string `json:"mention_roles"`
+	MentionRoles []Snowflake `json:"mention_roles"`
 	// Attachments []Attachment `json:"attachments,omitempty"`
-	// Embeds []Embed `json:"embeds,omitempty"`
-	// Reactions *[]Reaction `json:"reactions,omitempty"`
-	Nonce      *string `json:"nonce,omitempty"`
-	Pinned     bool    `json:"pinned"`
-	Webhook_id *string `json:"webhook_id,omitempty"`
-	Type       int     `json:"type"`
+	Embeds     []Embed    `json:"embeds,omitempty"`
+	Reactions  []Reaction `json:"reactions,omitempty"`
+	Nonce      *string    `json:"nonce,omitempty"`
+	Pinned     bool       `json:"pinned"`
+	Webhook_id *Snowflake `json:"webhook_id,omitempty"`
+	Type       int        `json:"type"`
 	// Activity *MessageActivity `json:"activity,omitempty"`
 	// Application *MessageApplication `json:"application,omitempty"`
+	// Combination of MessageFlag values.
+	Flags int `json:"flags,omitempty"`
 }
 
 type OutgoingMessage struct {
@@ -78,48 +92,231 @@ type OutgoingMessage struct {
 	Nonce   *string `json:"nonce,omitempty"`
 	Tts     bool    `json:"tts"`
 	// File multipart.File `json:"file,omitempty"`
-	// Embed Embed `json:"embed,omitempty"``
+	Embed *Embed `json:"embed,omitempty"`
 	// PayloadJson multipart.Form `json:"payload_string,omitempty"`
 }
 
 const channelsEnpoint = "/channels"
 
-// Send message on channel
-// TODO: fix up, migrate common logic into central client function.
-func (client *DiscordClient) SendMessage(channelId string, message OutgoingMessage) (sentMessage Message, err error) {
-	url := fmt.Sprintf("%s/v%d/channels/%s/messages", baseUrl, apiVersion, channelId)
+// Gets a channel by ID.
+// Reference: https://discordapp.com/developers/docs/resources/channel#get-channel
+func (client *DiscordClient) GetChannel(channelId Snowflake) (channel Channel, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s", channelsEnpoint, channelId),
+		result:   &channel,
+	})
+	return
+}
 
-	log.Print("Create message URL: ", url)
+// Changes to a channel's settings.
+// Reference: https://discordapp.com/developers/docs/resources/channel#modify-channel-json-params
+type ChannelPatch struct {
+	fields jsonPatch
+}
 
-	var bodyBytes []byte
-	bodyBytes, err = json.Marshal(&message)
+// 2-100 characters.
+func (p *ChannelPatch) SetName(name string) *ChannelPatch {
+	p.fields.set("name", name)
+	return p
+}
 
-	var req *http.Request
-	req, err = http.NewRequest(http.MethodPost, url, bytes.NewReader(bodyBytes))
+// Text channels only. Nil clears the topic.
+func (p *ChannelPatch) SetTopic(topic *string) *ChannelPatch {
+	p.fields.set("topic", topic)
+	return p
+}
 
-	if err != nil {
-		return
+// Text channels only.
+func (p *ChannelPatch) SetNsfw(nsfw bool) *ChannelPatch {
+	p.fields.set("nsfw", nsfw)
+	return p
+}
+
+func (p *ChannelPatch) SetPosition(position int) *ChannelPatch {
+	p.fields.set("position", position)
+	return p
+}
+
+// Voice channels only, in bits per second.
+func (p *ChannelPatch) SetBitrate(bitrate int) *ChannelPatch {
+	p.fields.set("bitrate", bitrate)
+	return p
+}
+
+// Voice channels only. 0 means no limit.
+func (p *ChannelPatch) SetUserLimit(userLimit int) *ChannelPatch {
+	p.fields.set("user_limit", userLimit)
+	return p
+}
+
+// Category the channel is in. Nil moves the channel out of its category.
+func (p *ChannelPatch) SetParentId(parentId *Snowflake) *ChannelPatch {
+	p.fields.set("parent_id", parentId)
+	return p
+}
+
+// Replaces all of the channel's permission overwrites.
+func (p *ChannelPatch) SetPermissionOverwrites(overwrites []Overwrite) *ChannelPatch {
+	if overwrites == nil {
+		overwrites = []Overwrite{}
 	}
+	p.fields.set("permission_overwrites", overwrites)
+	return p
+}
+
+// Text channels only, 0-21600 seconds. 0 disables slowmode.
+func (p *ChannelPatch) SetRateLimitPerUser(seconds int) *ChannelPatch {
+	p.fields.set("rate_limit_per_user", seconds)
+	return p
+}
 
-	req.Header.Add("Authorization", fmt.Sprintf("%s %s", authTokenType, client.AuthToken))
-	req.Header.Add("User-Agent", userAgent)
-	req.Header.Add("Content-Type", "application/json")
+func (p ChannelPatch) MarshalJSON() ([]byte, error) {
+	return p.fields.marshal()
+}
 
-	var resp *http.Response
-	resp, err = http.DefaultClient.Do(req)
+// Updates a channel's settings.
+// Reference: https://discordapp.com/developers/docs/resources/channel#modify-channel
+func (client *DiscordClient) ModifyChannel(channelId Snowflake, patch ChannelPatch, reason string) (channel Channel, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s", channelsEnpoint, channelId),
+		body:     patch,
+		reason:   reason,
+		result:   &channel,
+	})
+	return
+}
 
-	body, _ := ioutil.ReadAll(resp.Body)
-	log.Printf("Response: [%+v]. Body: [%s]", resp, body)
+// Deletes a guild channel or closes a DM, returning the deleted channel.
+// Reference: https://discordapp.com/developers/docs/resources/channel#deleteclose-channel
+func (client *DiscordClient) DeleteChannel(channelId Snowflake, reason string) (channel Channel, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s", channelsEnpoint, channelId),
+		reason:   reason,
+		result:   &channel,
+	})
+	return
+}
+
+// Send message on channel
+// Reference: https://discordapp.com/developers/docs/resources/channel#create-message
+func (client *DiscordClient) SendMessage(
+	channelId Snowflake, message OutgoingMessage, reason string,
+) (sentMessage Message, err error) {
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPost,
+		endpoint: fmt.Sprintf("%s/%s/messages", channelsEnpoint, channelId),
+		body:     &message,
+		reason:   reason,
+		result:   &sentMessage,
+	})
 
 	if err != nil {
-		return sentMessage, fmt.Errorf("failed to send message: %v", err)
+		return sentMessage, fmt.Errorf("failed to send message: %w", err)
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&sentMessage)
+	return
+}
 
-	if err == nil {
-		log.Print("Sent message response: ", sentMessage)
-	}
+// Reference: https://discordapp.com/developers/docs/resources/channel#get-pinned-messages
+func (client *DiscordClient) GetPinnedMessages(channelId Snowflake) (messages []Message, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/pins", channelsEnpoint, channelId),
+		result:   &messages,
+	})
+	return
+}
+
+// Channels can have at most 50 pinned messages.
+// Reference: https://discordapp.com/developers/docs/resources/channel#add-pinned-channel-message
+func (client *DiscordClient) PinMessage(channelId Snowflake, messageId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodPut,
+		endpoint: fmt.Sprintf("%s/%s/pins/%s", channelsEnpoint, channelId, messageId),
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#delete-pinned-channel-message
+func (client *DiscordClient) UnpinMessage(channelId Snowflake, messageId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/pins/%s", channelsEnpoint, channelId, messageId),
+		reason:   reason,
+	})
+}
+
+// Shows the current user as typing for 10 seconds, or until it sends a message.
+// Reference: https://discordapp.com/developers/docs/resources/channel#trigger-typing-indicator
+func (client *DiscordClient) TriggerTypingIndicator(channelId Snowflake, reason string) error {
+	return client.triggerTypingIndicator(context.Background(), channelId, reason)
+}
+
+func (client *DiscordClient) triggerTypingIndicator(ctx context.Context, channelId Snowflake, reason string) error {
+	return client.do(ctx, restRequest{
+		method:   http.MethodPost,
+		endpoint: fmt.Sprintf("%s/%s/typing", channelsEnpoint, channelId),
+		reason:   reason,
+	})
+}
+
+// Typing indicators last 10 seconds, so they are renewed a little sooner.
+const typingInterval = time.Duration(8) * time.Second
+
+// Shows the current user as typing until stop is called or the context is done, e.g. while a
+// long command runs. Sending a message also hides the indicator until the next renewal.
+func (client *DiscordClient) KeepTyping(ctx context.Context, channelId Snowflake) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(typingInterval)
+		defer ticker.Stop()
+
+		for {
+			// Stopping cancels a request in flight or waiting out a rate limit.
+			if err := client.triggerTypingIndicator(ctx, channelId, ""); err != nil && ctx.Err() == nil {
+				log.Print("Failed to trigger typing indicator: ", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#edit-channel-permissions-json-params
+type editChannelPermissions struct {
+	Allow Permissions `json:"allow"`
+	Deny  Permissions `json:"deny"`
+	Type  string      `json:"type"`
+}
+
+// Creates or replaces the channel's overwrite for the role or member.
+// Reference: https://discordapp.com/developers/docs/resources/channel#edit-channel-permissions
+func (client *DiscordClient) EditChannelPermissions(channelId Snowflake, overwrite Overwrite, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodPut,
+		endpoint: fmt.Sprintf("%s/%s/permissions/%s", channelsEnpoint, channelId, overwrite.Id),
+		body:     editChannelPermissions{Allow: overwrite.Allow, Deny: overwrite.Deny, Type: overwrite.Type},
+		reason:   reason,
+	})
+}
 
-	return sentMessage, err
+// Removes the channel's overwrite for the overwrite's role or member.
+// Reference: https://discordapp.com/developers/docs/resources/channel#delete-channel-permission
+func (client *DiscordClient) DeleteChannelPermission(channelId Snowflake, overwrite Overwrite, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/permissions/%s", channelsEnpoint, channelId, overwrite.Id),
+		reason:   reason,
+	})
 }
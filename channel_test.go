@@ -0,0 +1,132 @@
+package discordbot_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestModifyChannel(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"id": "41771983423143937", "type": 0, "name": "general"}
+	})
+
+	patch := discordbot.ChannelPatch{}
+	patch.SetName("general").SetTopic(nil).SetRateLimitPerUser(10)
+
+	client := api.client(t)
+	channel, err := client.ModifyChannel(41771983423143937, patch, "tidy up: topics")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if channel.Name == nil || *channel.Name != "general" {
+		t.Errorf("unexpected channel %+v", channel)
+	}
+
+	request := api.single(t)
+	if request.Method != http.MethodPatch || request.Path != "/channels/41771983423143937" {
+		t.Errorf("unexpected request %s %s", request.Method, request.Path)
+	}
+	if reason := request.Header.Get("X-Audit-Log-Reason"); reason != "tidy%20up:%20topics" {
+		t.Errorf("unexpected audit log reason [%s]", reason)
+	}
+
+	// Fields that weren't set must be left out, while the cleared topic is sent as null.
+	body := map[string]json.RawMessage{}
+	if err := json.Unmarshal(request.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != 3 || string(body["topic"]) != "null" || string(body["name"]) != `"general"` ||
+		string(body["rate_limit_per_user"]) != "10" {
+		t.Errorf("unexpected body %s", request.Body)
+	}
+}
+
+func TestDeleteChannel(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"id": "41771983423143937", "type": 0}
+	})
+
+	client := api.client(t)
+	channel, err := client.DeleteChannel(41771983423143937, "")
+
+	if err != nil || channel.Id != 41771983423143937 {
+		t.Fatalf("unexpected channel %+v: %v", channel, err)
+	}
+
+	request := api.single(t)
+	if request.Method != http.MethodDelete || request.Header.Get("X-Audit-Log-Reason") != "" {
+		t.Errorf("unexpected request %+v", request)
+	}
+}
+
+func TestEditChannelPermissions(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNoContent, nil
+	})
+
+	overwrite := discordbot.Overwrite{Id: 2, Type: discordbot.OverwriteTypeRole, Allow: 0x400, Deny: 0x800}
+
+	client := api.client(t)
+	if err := client.EditChannelPermissions(1, overwrite, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	request := api.single(t)
+	if request.Method != http.MethodPut || request.Path != "/channels/1/permissions/2" ||
+		string(request.Body) != `{"allow":1024,"deny":2048,"type":"role"}` {
+		t.Errorf("unexpected request %s %s %s", request.Method, request.Path, request.Body)
+	}
+}
+
+func TestKeepTyping(t *testing.T) {
+	typing := make(chan bool, 10)
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		if request.Method == http.MethodPost && request.Path == "/channels/1/typing" {
+			typing <- true
+		}
+		return http.StatusNoContent, nil
+	})
+
+	client := api.client(t)
+	stop := client.KeepTyping(context.Background(), 1)
+
+	select {
+	case <-typing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("typing indicator was not triggered")
+	}
+
+	stop()
+}
+
+func TestKeepTypingStopCancelsRequest(t *testing.T) {
+	started := make(chan bool, 1)
+	cancelled := make(chan bool, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- true
+		select {
+		case <-r.Context().Done():
+			cancelled <- true
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := discordbot.DiscordClient{AuthToken: t.Name(), BaseUrl: server.URL}
+	stop := client.KeepTyping(context.Background(), 1)
+	<-started
+	stop()
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stopping did not cancel the request in flight")
+	}
+}
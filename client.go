@@ -1,11 +1,15 @@
+// Package discordbot is a client for the Discord REST API and gateway.
+//
+// Methods that change data take a trailing reason, which is shown in the guild's audit log if
+// not empty. Patch types such as ChannelPatch only send the fields that were set, so the rest
+// are left unchanged.
 package discordbot
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
 )
 
 // Refer to https://discordapp.com/developers/docs/reference
@@ -16,42 +20,41 @@ const userAgent = "DiscordGoBot 0.0.1"
 
 type DiscordClient struct {
 	AuthToken string
+	// API root without the version, e.g. for tests. Defaults to the Discord API.
+	BaseUrl string
+	// Defaults to http.DefaultClient.
+	HttpClient *http.Client
+	// DM channel IDs by recipient, shared by copies of the client. Only set by NewDiscordClient.
+	dmChannels *dmChannelCache
+}
+
+// Clients created here cache DM channels for SendDirectMessage, unlike ones declared directly.
+func NewDiscordClient(authToken string) DiscordClient {
+	return DiscordClient{AuthToken: authToken, dmChannels: newDmChannelCache()}
 }
 
 const botGetGatewayEndpoint = "/gateway/bot"
 
 // Gateway connection details.
 // https://discordapp.com/developers/docs/topics/gateway#get-gateway-bot
-type gatewayInfo struct {
+type GatewayInfo struct {
 	Url    string `json:"url"`
 	Shards int    `json:"shards"`
 }
 
-func (client *DiscordClient) GetGateway() (gateway gatewayInfo, err error) {
-	url := baseUrl + "/v" + strconv.FormatInt(apiVersion, 10) + botGetGatewayEndpoint
-
-	log.Print("Get gateway URL: ", url)
-
-	var req *http.Request
-	req, err = http.NewRequest(http.MethodGet, url, nil)
-
-	if err != nil {
-		return
-	}
-
-	req.Header.Add("Authorization", fmt.Sprintf("%s %s", authTokenType, client.AuthToken))
-	req.Header.Add("User-Agent", userAgent)
-
-	var resp *http.Response
-	resp, err = http.DefaultClient.Do(req)
+func (client *DiscordClient) GetGateway() (gateway GatewayInfo, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: botGetGatewayEndpoint,
+		result:   &gateway,
+	})
 
 	if err != nil {
-		return gateway, fmt.Errorf("failed to get gateway: %v", err)
+		return gateway, fmt.Errorf("failed to get gateway: %w", err)
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&gateway)
 	log.Print("Gateway response: ", gateway)
-	return gateway, err
+	return
 }
 
 // TODO: add webhook support
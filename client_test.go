@@ -1,7 +1,14 @@
 package discordbot_test
 
 import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gdewald/discordbot"
@@ -18,3 +25,130 @@ func TestGetGateway(t *testing.T) {
 
 	t.Log(gateway, err)
 }
+
+// REST request received by the fake API.
+type fakeApiRequest struct {
+	Method string
+	// Escaped path after the API version.
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// Fake Discord REST API that records requests and replies with the given function.
+type fakeApi struct {
+	server   *httptest.Server
+	mutex    sync.Mutex
+	requests []fakeApiRequest
+}
+
+// Replies with the status and the JSON encoded body. A nil body sends no content.
+type fakeApiResponder func(request fakeApiRequest) (status int, body interface{})
+
+func newFakeApi(t *testing.T, respond fakeApiResponder) *fakeApi {
+	f := &fakeApi{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		request := fakeApiRequest{
+			Method: r.Method,
+			Path:   strings.TrimPrefix(r.URL.EscapedPath(), "/v6"),
+			Query:  r.URL.RawQuery,
+			Header: r.Header,
+			Body:   body,
+		}
+
+		f.mutex.Lock()
+		f.requests = append(f.requests, request)
+		f.mutex.Unlock()
+
+		status, response := respond(request)
+		if response == nil {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// Each client gets its own token so that rate limits don't carry over between tests.
+func (f *fakeApi) client(t *testing.T) discordbot.DiscordClient {
+	client := discordbot.NewDiscordClient(t.Name())
+	client.BaseUrl = f.server.URL
+	return client
+}
+
+func (f *fakeApi) received() []fakeApiRequest {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]fakeApiRequest(nil), f.requests...)
+}
+
+// Returns the only request received, failing if there were others.
+func (f *fakeApi) single(t *testing.T) fakeApiRequest {
+	requests := f.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d: %+v", len(requests), requests)
+	}
+	return requests[0]
+}
+
+func TestRestRetriesAfterRateLimit(t *testing.T) {
+	attempts := 0
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		attempts++
+		if attempts == 1 {
+			return http.StatusTooManyRequests, map[string]interface{}{"retry_after": 50, "global": false}
+		}
+		return http.StatusOK, map[string]interface{}{"url": "wss://gateway.discord.gg", "shards": 1}
+	})
+
+	client := api.client(t)
+	gateway, err := client.GetGateway()
+
+	if err != nil || gateway.Url != "wss://gateway.discord.gg" {
+		t.Fatalf("unexpected gateway %+v: %v", gateway, err)
+	}
+
+	requests := api.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected a retry after being rate limited, got %d requests", len(requests))
+	}
+	if requests[1].Header.Get("Authorization") != "Bot "+t.Name() {
+		t.Errorf("unexpected authorization header [%s]", requests[1].Header.Get("Authorization"))
+	}
+}
+
+func TestRestError(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNotFound, map[string]interface{}{"code": 10003, "message": "Unknown Channel"}
+	})
+
+	client := api.client(t)
+	_, err := client.GetChannel(1)
+
+	restErr := &discordbot.RestError{}
+	if !errors.As(err, &restErr) {
+		t.Fatalf("expected a RestError, got %v", err)
+	}
+	if restErr.StatusCode != http.StatusNotFound || restErr.Code != 10003 || restErr.Message != "Unknown Channel" {
+		t.Errorf("unexpected error %+v", restErr)
+	}
+
+	// Wrapped errors still expose the RestError.
+	_, err = client.SendMessage(1, discordbot.OutgoingMessage{Content: "hi"}, "")
+	if !errors.As(err, &restErr) || restErr.Code != 10003 {
+		t.Errorf("expected a wrapped RestError, got %v", err)
+	}
+
+	_, err = client.GetGateway()
+	if !errors.As(err, &restErr) || restErr.Code != 10003 {
+		t.Errorf("expected a wrapped RestError, got %v", err)
+	}
+}
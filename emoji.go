@@ -0,0 +1,106 @@
+package discordbot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/emoji#list-guild-emojis
+func (client *DiscordClient) ListGuildEmojis(guildId Snowflake) (emojis []Emoji, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/emojis", guildsEndpoint, guildId),
+		result:   &emojis,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/emoji#get-guild-emoji
+func (client *DiscordClient) GetGuildEmoji(guildId Snowflake, emojiId Snowflake) (emoji Emoji, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/emojis/%s", guildsEndpoint, guildId, emojiId),
+		result:   &emoji,
+	})
+	return
+}
+
+// Emoji images are limited to 256 KiB.
+const maxEmojiSize = 256 * 1024
+
+// Reference: https://discordapp.com/developers/docs/resources/emoji#create-guild-emoji-json-params
+type createGuildEmoji struct {
+	Name  string      `json:"name"`
+	Image string      `json:"image"`
+	Roles []Snowflake `json:"roles,omitempty"`
+}
+
+// Creates an emoji from a PNG, JPEG or GIF image of up to 256 KiB. If roleIds is not empty, only
+// members with one of the roles can use the emoji.
+// Reference: https://discordapp.com/developers/docs/resources/emoji#create-guild-emoji
+func (client *DiscordClient) CreateGuildEmoji(
+	guildId Snowflake, name string, image io.Reader, roleIds []Snowflake, reason string,
+) (emoji Emoji, err error) {
+
+	imageData, err := imageDataUri(image, maxEmojiSize, imageDataTypes)
+
+	if err != nil {
+		return emoji, fmt.Errorf("invalid emoji image: %v", err)
+	}
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPost,
+		endpoint: fmt.Sprintf("%s/%s/emojis", guildsEndpoint, guildId),
+		body:     createGuildEmoji{Name: name, Image: imageData, Roles: roleIds},
+		reason:   reason,
+		result:   &emoji,
+	})
+	return
+}
+
+// Changes to an emoji.
+// Reference: https://discordapp.com/developers/docs/resources/emoji#modify-guild-emoji-json-params
+type EmojiPatch struct {
+	fields jsonPatch
+}
+
+func (p *EmojiPatch) SetName(name string) *EmojiPatch {
+	p.fields.set("name", name)
+	return p
+}
+
+// Roles allowed to use the emoji. Nil allows everyone.
+func (p *EmojiPatch) SetRoles(roleIds []Snowflake) *EmojiPatch {
+	p.fields.set("roles", roleIds)
+	return p
+}
+
+func (p EmojiPatch) MarshalJSON() ([]byte, error) {
+	return p.fields.marshal()
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/emoji#modify-guild-emoji
+func (client *DiscordClient) ModifyGuildEmoji(
+	guildId Snowflake, emojiId Snowflake, patch EmojiPatch, reason string,
+) (emoji Emoji, err error) {
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s/emojis/%s", guildsEndpoint, guildId, emojiId),
+		body:     patch,
+		reason:   reason,
+		result:   &emoji,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/emoji#delete-guild-emoji
+func (client *DiscordClient) DeleteGuildEmoji(guildId Snowflake, emojiId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/emojis/%s", guildsEndpoint, guildId, emojiId),
+		reason:   reason,
+	})
+}
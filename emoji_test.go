@@ -0,0 +1,86 @@
+package discordbot_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+// Enough of a PNG for content type detection.
+var pngImage = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+func TestCreateGuildEmoji(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusCreated, map[string]interface{}{"id": "3", "name": "blob"}
+	})
+
+	client := api.client(t)
+	emoji, err := client.CreateGuildEmoji(1, "blob", bytes.NewReader(pngImage), []discordbot.Snowflake{2}, "")
+
+	if err != nil || emoji.Name != "blob" {
+		t.Fatalf("unexpected emoji %+v: %v", emoji, err)
+	}
+
+	body := struct {
+		Image string                 `json:"image"`
+		Roles []discordbot.Snowflake `json:"roles"`
+	}{}
+	json.Unmarshal(api.single(t).Body, &body)
+
+	if !strings.HasPrefix(body.Image, "data:image/png;base64,iVBORw0KGgo") || len(body.Roles) != 1 {
+		t.Errorf("unexpected body %+v", body)
+	}
+
+	if _, err := client.CreateGuildEmoji(1, "blob", strings.NewReader("not an image"), nil, ""); err == nil {
+		t.Error("expected an error for a text file")
+	}
+
+	large := io.MultiReader(bytes.NewReader(pngImage), bytes.NewReader(make([]byte, 256*1024)))
+	if _, err := client.CreateGuildEmoji(1, "blob", large, nil, ""); err == nil {
+		t.Error("expected an error for an image over 256 KiB")
+	}
+
+	if requests := api.received(); len(requests) != 1 {
+		t.Errorf("invalid images should not be sent, got %d requests", len(requests))
+	}
+}
+
+func TestCreateGuildSticker(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"id": "4", "name": "wave", "tags": "wave", "type": 2, "format_type": 3}
+	})
+
+	options := discordbot.StickerOptions{Name: "wave", Description: "Waving", Tags: "wave"}
+
+	client := api.client(t)
+	sticker, err := client.CreateGuildSticker(1, options, strings.NewReader(`{"v": "5.5.2", "layers": []}`), "")
+
+	if err != nil || sticker.FormatType != discordbot.StickerFormatLottie {
+		t.Fatalf("unexpected sticker %+v: %v", sticker, err)
+	}
+
+	request := api.single(t)
+	_, params, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(request.Body), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if form.Value["name"][0] != "wave" || form.Value["description"][0] != "Waving" || len(form.File["file"]) != 1 {
+		t.Errorf("unexpected form %+v", form)
+	}
+	if file := form.File["file"][0]; file.Filename != "sticker.json" || file.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected file %s of type %s", file.Filename, file.Header.Get("Content-Type"))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"net/http"
 	"runtime"
 	"sync"
@@ -74,18 +75,43 @@ const gatewayEncoding = "json"
 
 type DiscordGateway struct {
 	DiscordClient
-	GatewayInfo gatewayInfo
-	// TODO: use sync.Map
+	GatewayInfo GatewayInfo
+	// Optional cache updated from events before listeners are called. Must be set before Connect.
+	// Events are queued for the cache so that a slow store doesn't hold up reading the connection,
+	// and their listeners are called once the cache has them, so they lag behind while it catches up.
+	StateCache *State
+	// Guards the listener maps, which may be registered to while payloads are dispatched.
+	listenerMutex   sync.RWMutex
 	opcodeListeners map[int][]GatewayMessageListener
 	eventListeners  map[string][]GatewayMessageListener
 	conn            *websocket.Conn
 	connMutex       *sync.Mutex
-	heartbeat       *discordHeartbeat
-	sessionId       *string
-	sequenceNumber  *int
+	reconnectMutex  *sync.Mutex
+	// Closed by Close to stop reconnecting. Closed while holding connMutex so that a reconnect
+	// can't install a new connection after Close.
+	closed         chan struct{}
+	heartbeat      *discordHeartbeat
+	latency        *heartbeatLatency
+	ready          chan gatewayReadyResponse
+	session        gatewaySession
+	statusLimiter  *rateLimiter
+	memberRequests *guildMemberRequests
+	voiceJoins     *voiceJoinRequests
+	// Dispatches waiting for StateCache, in the order they were received.
+	dispatches chan GatewayPayload
 }
 
+// Dispatches that can wait for the state cache before reading the connection blocks.
+const dispatchQueueSize = 1000
+
+// Most dispatches the state cache ingests in one batch.
+const dispatchBatchSize = 100
+
 func (g *DiscordGateway) SendPayload(payload *GatewayPayload) (err error) {
+	if err = g.checkConnected("send payloads"); err != nil {
+		return
+	}
+
 	g.connMutex.Lock()
 
 	log.Printf("Sending payload with Opcode [%v], event name [%s], data [%s], and sequenceNum [%v].",
@@ -102,6 +128,10 @@ func (g *DiscordGateway) SendPayload(payload *GatewayPayload) (err error) {
 }
 
 func (g *DiscordGateway) SendControl(messageType int, data []byte, deadline time.Time) (err error) {
+	if err = g.checkConnected("send control messages"); err != nil {
+		return
+	}
+
 	g.connMutex.Lock()
 
 	err = g.conn.WriteControl(messageType, data, deadline)
@@ -110,6 +140,34 @@ func (g *DiscordGateway) SendControl(messageType int, data []byte, deadline time
 	return
 }
 
+// Commands need what Connect sets up, even once the connection has been lost or closed.
+func (g *DiscordGateway) checkConnected(action string) error {
+	if g.connMutex == nil {
+		return fmt.Errorf("cannot %s before connecting", action)
+	}
+	return nil
+}
+
+// Sends a heartbeat with the last sequence number received.
+// Reference: https://discordapp.com/developers/docs/topics/gateway#heartbeat
+func (g *DiscordGateway) sendHeartbeat() error {
+	sequenceJsonBytes, err := json.Marshal(g.session.lastSequence())
+
+	if err != nil {
+		return err
+	}
+
+	return g.SendPayload(&GatewayPayload{
+		Opcode:    OpcodeHeartbeat,
+		EventData: sequenceJsonBytes,
+	})
+}
+
+// Returns the current connection state.
+func (g *DiscordGateway) State() GatewayState {
+	return g.session.getState()
+}
+
 // Reference: https://discordapp.com/developers/docs/topics/gateway#payloads-gateway-payload-structure
 type GatewayPayload struct {
 	Opcode         int             `json:"op"`
@@ -122,6 +180,9 @@ type GatewayMessageListener func(GatewayPayload)
 
 // Register listener that is called when the opcode is received.
 func (g *DiscordGateway) RegisterOpcodeListener(opcode int, listener GatewayMessageListener) {
+	g.listenerMutex.Lock()
+	defer g.listenerMutex.Unlock()
+
 	if g.opcodeListeners == nil {
 		g.opcodeListeners = make(map[int][]GatewayMessageListener)
 	}
@@ -130,6 +191,9 @@ func (g *DiscordGateway) RegisterOpcodeListener(opcode int, listener GatewayMess
 
 // Register listener that is called when a named event is received (OpcodeDispatch only).
 func (g *DiscordGateway) RegisterEventListener(event string, listener GatewayMessageListener) {
+	g.listenerMutex.Lock()
+	defer g.listenerMutex.Unlock()
+
 	if g.eventListeners == nil {
 		g.eventListeners = make(map[string][]GatewayMessageListener)
 	}
@@ -138,17 +202,81 @@ func (g *DiscordGateway) RegisterEventListener(event string, listener GatewayMes
 
 // Connects to gateway, starts heartbeat, initializes listeners for gateway.
 func (g *DiscordGateway) Connect() (err error) {
+	g.connMutex = new(sync.Mutex)
+	g.reconnectMutex = new(sync.Mutex)
+	g.closed = make(chan struct{})
+	g.latency = new(heartbeatLatency)
+	g.ready = make(chan gatewayReadyResponse, 1)
+	g.statusLimiter = newRateLimiter(statusUpdateLimit, statusUpdateWindow)
+	g.memberRequests = newGuildMemberRequests()
+	g.voiceJoins = newVoiceJoinRequests()
+	if g.StateCache != nil {
+		g.dispatches = make(chan GatewayPayload, dispatchQueueSize)
+		go g.dispatchLoop(g.dispatches, g.closed)
+	}
+
+	// The heartbeat is replaced on reconnect, so always forward to the current one.
+	g.RegisterOpcodeListener(OpcodeHeartbeatACK, func(payload GatewayPayload) {
+		g.currentHeartbeat().heartbeatAckRecv(payload)
+	})
+	g.RegisterOpcodeListener(OpcodeHeartbeat, func(payload GatewayPayload) {
+		g.currentHeartbeat().heartbeatRecv(payload)
+	})
+	g.RegisterOpcodeListener(OpcodeReconnect, g.reconnectRecv)
+	g.RegisterOpcodeListener(OpcodeInvalidSession, g.invalidSessionRecv)
+
+	g.RegisterEventListener(EventReady, g.readyRecv)
+	g.RegisterEventListener(EventResumed, func(GatewayPayload) {
+		g.session.setState(GatewayStateReady)
+	})
+	g.RegisterEventListener(EventGuildMembersChunk, g.memberRequests.chunkRecv)
+	g.RegisterEventListener(EventVoiceStateUpdate, g.voiceJoins.stateRecv)
+	g.RegisterEventListener(EventVoiceServerUpdate, g.voiceJoins.serverRecv)
+
+	g.RegisterOpcodeListener(OpcodeDispatch, func(payload GatewayPayload) {
+		listeners := g.listenersForEvent(payload.EventName)
+		log.Printf("Found [%d] listeners for event [%v]", len(listeners), payload.EventName)
+		for _, eventListener := range listeners {
+			log.Print("Calling event listener.", eventListener)
+			go eventListener(payload)
+		}
+	})
+
+	g.session.setState(GatewayStateConnecting)
+	err = g.open(g.GatewayInfo.Url)
+
+	if err != nil {
+		g.session.setState(GatewayStateDisconnected)
+		return
+	}
+
+	g.session.setState(GatewayStateConnected)
+	return
+}
+
+func (g *DiscordGateway) listenersForEvent(event string) []GatewayMessageListener {
+	g.listenerMutex.RLock()
+	defer g.listenerMutex.RUnlock()
+	return g.eventListeners[event]
+}
+
+func (g *DiscordGateway) listenersForOpcode(opcode int) []GatewayMessageListener {
+	g.listenerMutex.RLock()
+	defer g.listenerMutex.RUnlock()
+	return g.opcodeListeners[opcode]
+}
+
+// Dials the gateway and waits for hello, then starts the heartbeat and read loop for the new connection.
+func (g *DiscordGateway) open(gatewayUrl string) (err error) {
 	dialer := websocket.Dialer{}
 
-	connectUrl := g.GatewayInfo.Url + fmt.Sprintf("/?v=%d&encoding=%s", gatewayVersion, gatewayEncoding)
+	connectUrl := gatewayUrl + fmt.Sprintf("/?v=%d&encoding=%s", gatewayVersion, gatewayEncoding)
 	connectHeader := http.Header{}
 
 	connectHeader.Add("Authorization", fmt.Sprintf("%s %s", authTokenType, g.AuthToken))
 	connectHeader.Add("User-Agent", userAgent)
 
-	var resp *http.Response
-	g.conn, resp, err = dialer.Dial(connectUrl, connectHeader)
-	g.connMutex = new(sync.Mutex)
+	conn, resp, err := dialer.Dial(connectUrl, connectHeader)
 	log.Printf("Response: [%+v].", resp)
 
 	if err != nil {
@@ -157,14 +285,16 @@ func (g *DiscordGateway) Connect() (err error) {
 
 	// First message should be a hello with heartbeat details.
 	helloResp := new(GatewayPayload)
-	err = g.conn.ReadJSON(helloResp)
+	err = conn.ReadJSON(helloResp)
 
 	if err != nil {
+		conn.Close()
 		return fmt.Errorf("did not receive hello: %v", err)
 	}
 	log.Printf("First recv: [%+v].", helloResp)
 
 	if helloResp.Opcode != OpcodeHello {
+		conn.Close()
 		return fmt.Errorf("not a hello opcode. Instead got message [%+v]", helloResp)
 	}
 
@@ -172,55 +302,320 @@ func (g *DiscordGateway) Connect() (err error) {
 	err = json.Unmarshal(helloResp.EventData, &helloMessage)
 
 	if err != nil {
+		conn.Close()
 		return fmt.Errorf("unable to parse gateway hello response [%+v]", helloResp.EventData)
 	}
 
 	heartbeatInterval := time.Duration(helloMessage.HeartbeatInterval) * time.Millisecond
 
 	if heartbeatInterval <= 0 {
+		conn.Close()
 		return fmt.Errorf("invalid heartbeat interval [%v] in hello", heartbeatInterval)
 	}
 
-	g.heartbeat = &discordHeartbeat{
-		gateway:  g,
-		interval: heartbeatInterval,
-		getSequenceNum: func() *int {
-			return g.sequenceNumber
-		},
+	heartbeat := newDiscordHeartbeat(gatewayHeartbeatConn{gateway: g, conn: conn}, g.latency)
+
+	g.connMutex.Lock()
+	select {
+	case <-g.closed:
+		g.connMutex.Unlock()
+		conn.Close()
+		return fmt.Errorf("gateway was closed")
+	default:
 	}
+	g.conn = conn
+	g.heartbeat = heartbeat
+	g.connMutex.Unlock()
 
-	startHeartbeat(g.heartbeat)
-	g.RegisterOpcodeListener(OpcodeHeartbeatACK, g.heartbeat.heartbeatAckRecv)
-	g.RegisterOpcodeListener(OpcodeHeartbeat, g.heartbeat.heartbeatRecv)
+	startHeartbeat(heartbeat, heartbeatInterval)
+	go g.readLoop(conn)
 
-	g.RegisterOpcodeListener(OpcodeDispatch, func(payload GatewayPayload) {
-		g.sequenceNumber = payload.SequenceNumber
-		listeners := g.eventListeners[payload.EventName]
-		log.Printf("Found [%d] listeners for event [%v]", len(listeners), payload.EventName)
-		for _, eventListener := range listeners {
-			log.Print("Calling event listener.", eventListener)
-			go eventListener(payload)
+	return
+}
+
+func (g *DiscordGateway) readLoop(conn *websocket.Conn) {
+	for {
+		payload := GatewayPayload{}
+		err := conn.ReadJSON(&payload)
+
+		if err != nil {
+			log.Print("Failure reading message: ", err)
+			g.reconnect(conn)
+			return
 		}
-	})
 
-	go func() {
-		for {
-			payload := GatewayPayload{}
-			err := g.conn.ReadJSON(&payload)
+		log.Printf("Received payload with Opcode [%v], event name [%s], data [%s], and sequenceNum [%v].",
+			payload.Opcode, payload.EventName, payload.EventData, payload.SequenceNumber)
+
+		// Handled here rather than in a listener so that events are seen in order.
+		if payload.Opcode == OpcodeDispatch {
+			g.session.advanceSequence(payload.SequenceNumber)
 
-			if err != nil {
-				log.Fatal("Failure reading message.", err)
+			if g.dispatches != nil {
+				select {
+				case g.dispatches <- payload:
+				case <-g.closed:
+				}
+				continue
 			}
+		}
+
+		g.notifyListeners(payload)
+	}
+}
 
-			log.Printf("Received payload with Opcode [%v], event name [%s], data [%s], and sequenceNum [%v].",
-				payload.Opcode, payload.EventName, payload.EventData, payload.SequenceNumber)
+// Ingests queued dispatches into the state cache in batches, then calls their listeners in order.
+func (g *DiscordGateway) dispatchLoop(dispatches chan GatewayPayload, closed chan struct{}) {
+	for {
+		var batch []GatewayPayload
+		select {
+		case payload := <-dispatches:
+			batch = append(batch, payload)
+		case <-closed:
+			return
+		}
 
-			log.Printf("Found [%d] listeners for opcode", len(g.opcodeListeners[payload.Opcode]))
-			for _, opcodeListener := range g.opcodeListeners[payload.Opcode] {
-				go opcodeListener(payload)
+	drain:
+		for len(batch) < dispatchBatchSize {
+			select {
+			case payload := <-dispatches:
+				batch = append(batch, payload)
+			default:
+				break drain
 			}
 		}
-	}()
+
+		g.StateCache.ingest(batch...)
+		for _, payload := range batch {
+			g.notifyListeners(payload)
+		}
+	}
+}
+
+func (g *DiscordGateway) notifyListeners(payload GatewayPayload) {
+	listeners := g.listenersForOpcode(payload.Opcode)
+	log.Printf("Found [%d] listeners for opcode", len(listeners))
+	for _, opcodeListener := range listeners {
+		go opcodeListener(payload)
+	}
+}
+
+func (g *DiscordGateway) currentConn() *websocket.Conn {
+	g.connMutex.Lock()
+	defer g.connMutex.Unlock()
+	return g.conn
+}
+
+func (g *DiscordGateway) currentHeartbeat() *discordHeartbeat {
+	g.connMutex.Lock()
+	defer g.connMutex.Unlock()
+	return g.heartbeat
+}
+
+// Heartbeat hooks for a single gateway connection, so that failures of a replaced connection are ignored.
+type gatewayHeartbeatConn struct {
+	gateway *DiscordGateway
+	conn    *websocket.Conn
+}
+
+func (c gatewayHeartbeatConn) sendHeartbeat() error {
+	return c.gateway.sendHeartbeat()
+}
+
+func (c gatewayHeartbeatConn) heartbeatFailed(err error) {
+	c.gateway.reconnect(c.conn)
+}
+
+// Returns the round trip time of the last acknowledged heartbeat, and a smoothed average over
+// recent heartbeats. Both are zero until the first ack is received.
+func (g *DiscordGateway) Latency() (last time.Duration, smoothed time.Duration) {
+	if g.latency == nil {
+		return
+	}
+	return g.latency.get()
+}
+
+// Delay between failed reconnect attempts, doubling up to the max.
+const reconnectInitialBackoff = time.Duration(1) * time.Second
+const reconnectMaxBackoff = time.Duration(60) * time.Second
+
+// Replaces a failed connection with a new one and resumes the session, if there is one.
+// Does nothing if the connection was already replaced.
+func (g *DiscordGateway) reconnect(failed *websocket.Conn) {
+	g.reconnectMutex.Lock()
+	defer g.reconnectMutex.Unlock()
+
+	if g.currentConn() != failed || g.isClosed() {
+		return
+	}
+
+	log.Print("Reconnecting to gateway.")
+	g.session.setState(GatewayStateReconnecting)
+	stopHeartbeat(g.currentHeartbeat())
+
+	// Closing with a code other than 1000 keeps the session resumable.
+	err := failed.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(CloseSessionTimeout, ""),
+		time.Now().Add(closeTimeoutSeconds),
+	)
+
+	if err != nil {
+		log.Printf("gateway failed to close with error: %v", err)
+	}
+	failed.Close()
+
+	sessionId, _, resumable := g.session.resumeInfo()
+	gatewayUrl := g.GatewayInfo.Url
+	if resumeUrl := g.session.getResumeUrl(); resumable && resumeUrl != "" {
+		gatewayUrl = resumeUrl
+	}
+
+	backoff := reconnectInitialBackoff
+	for {
+		err = g.open(gatewayUrl)
+
+		if err == nil {
+			break
+		}
+
+		log.Printf("Failed to reconnect, retrying in [%v]: %v", backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-g.closed:
+			return
+		}
+
+		backoff *= 2
+		if backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+
+	g.session.setState(GatewayStateConnected)
+	_, identified := g.session.getIdentify()
+
+	if resumable {
+		log.Printf("Resuming session [%s].", sessionId)
+		err = g.resume()
+	} else if identified {
+		err = g.sendIdentify()
+	}
+
+	if err != nil {
+		log.Print("Failed to restore session after reconnect: ", err)
+	}
+}
+
+// Closes the connection and stops any reconnect in progress. The session ends and can't be resumed.
+func (g *DiscordGateway) Close() (err error) {
+	// Never connected.
+	if g.connMutex == nil {
+		return
+	}
+
+	g.connMutex.Lock()
+	if g.isClosed() {
+		g.connMutex.Unlock()
+		return
+	}
+	close(g.closed)
+	conn := g.conn
+	heartbeat := g.heartbeat
+	g.connMutex.Unlock()
+
+	g.session.setState(GatewayStateDisconnected)
+
+	// Connect may have failed before a connection was made.
+	if heartbeat != nil {
+		stopHeartbeat(heartbeat)
+	}
+	if conn == nil {
+		return
+	}
+
+	// Closing with 1000 ends the session.
+	g.SendControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(closeTimeoutSeconds),
+	)
+	return conn.Close()
+}
+
+func (g *DiscordGateway) isClosed() bool {
+	select {
+	case <-g.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Called when the gateway asks us to reconnect.
+func (g *DiscordGateway) reconnectRecv(GatewayPayload) {
+	log.Print("Gateway requested reconnect.")
+	g.reconnect(g.currentConn())
+}
+
+// Called when resuming or identifying fails. The event data says whether the session can still be resumed.
+// Reference: https://discordapp.com/developers/docs/topics/gateway#invalid-session
+func (g *DiscordGateway) invalidSessionRecv(payload GatewayPayload) {
+	resumable := false
+	json.Unmarshal(payload.EventData, &resumable)
+	log.Printf("Invalid session, resumable [%v].", resumable)
+
+	var err error
+	if resumable {
+		err = g.resume()
+	} else {
+		g.session.clear()
+
+		// Discord asks clients to wait a random 1-5 seconds before identifying again.
+		time.Sleep(time.Duration(1+rand.Intn(5)) * time.Second)
+		err = g.sendIdentify()
+	}
+
+	if err != nil {
+		log.Print("Failed to restore invalid session: ", err)
+	}
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#resume-resume-structure
+type gatewayResumeRequest struct {
+	Token     string `json:"token"`
+	SessionId string `json:"session_id"`
+	Sequence  *int   `json:"seq"`
+}
+
+// Resumes the current session, replaying events missed since the last sequence number.
+func (g *DiscordGateway) resume() (err error) {
+	sessionId, sequence, ok := g.session.resumeInfo()
+
+	if !ok {
+		return fmt.Errorf("no session to resume")
+	}
+
+	var requestJsonBytes json.RawMessage
+	requestJsonBytes, err = json.Marshal(&gatewayResumeRequest{
+		Token:     g.AuthToken,
+		SessionId: sessionId,
+		Sequence:  sequence,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal resume request: %v", err)
+	}
+
+	g.session.setState(GatewayStateResuming)
+	err = g.SendPayload(&GatewayPayload{
+		Opcode:    OpcodeResume,
+		EventData: requestJsonBytes,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to send resume request: %v", err)
+	}
 
 	return
 }
@@ -245,10 +640,10 @@ type gatewayConnectionProperties struct {
 
 // Reference: https://discordapp.com/developers/docs/topics/gateway#update-status-gateway-status-update-structure
 type GatewayStatusUpdate struct {
-	Since int `json:"since"`
-	// Game *Activity
-	Status string `json:"status"`
-	Afk    bool   `json:"afk"`
+	Since  int       `json:"since"`
+	Game   *Activity `json:"game"`
+	Status string    `json:"status"`
+	Afk    bool      `json:"afk"`
 }
 
 // Reference: https://discordapp.com/developers/docs/topics/gateway#update-status-status-types
@@ -280,6 +675,8 @@ type gatewayReadyResponse struct {
 	PrivateChannels []Channel `json:"private_channels"`
 	Guilds          []UnavailableGuild
 	SessionId       string `json:"session_id"`
+	// Only sent by newer gateway versions.
+	ResumeGatewayUrl string `json:"resume_gateway_url,omitempty"`
 }
 
 // How long to wait before giving up on identify event.
@@ -290,7 +687,34 @@ const enablePacketCompression = false
 
 // Sends identify to server and returns user from the ready response.
 func (g *DiscordGateway) Identify(initialStatus *GatewayStatusUpdate) (user User, err error) {
+	g.session.setIdentify(initialStatus)
+
+	// Drop a READY left over from re-identifying after a reconnect, so that only the response
+	// to this identify is returned.
+	select {
+	case <-g.ready:
+	default:
+	}
+
+	err = g.sendIdentify()
+
+	if err != nil {
+		return
+	}
+
+	select {
+	case readyMessage := <-g.ready:
+		user = readyMessage.User
+	case <-time.After(identifyTimeoutSeconds):
+		err = fmt.Errorf("Failed to get ready response for identify before timeout.")
+	}
 
+	return
+}
+
+// Sends identify with the status given to Identify. Also used to start a new session when the old one can't be resumed.
+func (g *DiscordGateway) sendIdentify() (err error) {
+	status, _ := g.session.getIdentify()
 	compress := enablePacketCompression
 	identifyRequest := gatewayIdentifyRequest{
 		Token: g.AuthToken,
@@ -300,43 +724,44 @@ func (g *DiscordGateway) Identify(initialStatus *GatewayStatusUpdate) (user User
 			Device:  "computer",
 		},
 		Compress: &compress,
-		Presence: initialStatus,
+		Presence: status,
 	}
 
 	var requestJsonBytes json.RawMessage
 	requestJsonBytes, err = json.Marshal(&identifyRequest)
 
 	if err != nil {
-		return user, fmt.Errorf("failed to marshal identify request: %v", err)
+		return fmt.Errorf("failed to marshal identify request: %v", err)
 	}
 
-	messageReceieved := make(chan error)
-	readyMessage := gatewayReadyResponse{}
-	g.RegisterEventListener(EventReady, func(readyPayload GatewayPayload) {
-		err = json.Unmarshal(readyPayload.EventData, &readyMessage)
-		messageReceieved <- err
-	})
-
+	g.session.setState(GatewayStateIdentifying)
+	g.session.resetSequence()
 	err = g.SendPayload(&GatewayPayload{
 		Opcode:    OpcodeIdentify,
 		EventData: requestJsonBytes,
 	})
 
 	if err != nil {
-		return user, fmt.Errorf("failed to send identify request: %v", err)
+		return fmt.Errorf("failed to send identify request: %v", err)
 	}
 
-	select {
-	case err = <-messageReceieved:
-		if err != nil {
-			return
-		}
+	return
+}
 
-		g.sessionId = &readyMessage.SessionId
-		user = readyMessage.User
-	case <-time.After(identifyTimeoutSeconds):
-		err = fmt.Errorf("Failed to get ready response for identify before timeout.")
+// Called on READY. Stores the new session and hands the ready message to Identify, if it is waiting.
+func (g *DiscordGateway) readyRecv(payload GatewayPayload) {
+	readyMessage := gatewayReadyResponse{}
+	err := json.Unmarshal(payload.EventData, &readyMessage)
+
+	if err != nil {
+		log.Print("Failed to parse ready event: ", err)
+		return
 	}
 
-	return
+	g.session.start(readyMessage.SessionId, readyMessage.User.Id, readyMessage.ResumeGatewayUrl)
+
+	select {
+	case g.ready <- readyMessage:
+	default:
+	}
 }
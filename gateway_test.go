@@ -2,12 +2,18 @@ package discordbot_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gdewald/discordbot"
+	"github.com/gorilla/websocket"
 )
 
 // Test should only be run manually - there is a limit on number of identify requests in a time period.
@@ -54,7 +60,7 @@ func TestConnectAndIdentify(t *testing.T) {
 
 			for _, channel := range channels {
 				if channel.Name != nil && *channel.Name == "general" {
-					sentMessage, err := client.SendMessage(channel.Id, message)
+					sentMessage, err := client.SendMessage(channel.Id, message, "")
 
 					if err != nil {
 						log.Print(err)
@@ -86,3 +92,343 @@ func TestConnectAndIdentify(t *testing.T) {
 
 	time.Sleep(time.Duration(5) * time.Minute)
 }
+
+// Local stand-in for the Discord gateway. Sends hello on connect and records every payload received.
+type fakeGateway struct {
+	server   *httptest.Server
+	received chan discordbot.GatewayPayload
+	conns    chan *websocket.Conn
+	// Server side of the most recent connection.
+	conn     *websocket.Conn
+	sequence int
+	// Guards writes, which may come from the test and from heartbeat acks.
+	writeMutex sync.Mutex
+	// Set by stopAcks, guarded by writeMutex.
+	acksStopped bool
+	// Times at which heartbeats were left unacknowledged.
+	unacked chan time.Time
+}
+
+type fakeGatewayOptions struct {
+	heartbeatIntervalMs int
+	ackHeartbeats       bool
+}
+
+func newFakeGateway(t *testing.T) *fakeGateway {
+	return newFakeGatewayWithOptions(t, fakeGatewayOptions{heartbeatIntervalMs: 60000})
+}
+
+func newFakeGatewayWithOptions(t *testing.T, options fakeGatewayOptions) *fakeGateway {
+	fake := &fakeGateway{
+		received: make(chan discordbot.GatewayPayload, 1000),
+		conns:    make(chan *websocket.Conn, 10),
+		unacked:  make(chan time.Time, 100),
+	}
+
+	upgrader := websocket.Upgrader{}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		fake.write(conn, discordbot.GatewayPayload{
+			Opcode:    discordbot.OpcodeHello,
+			EventData: json.RawMessage(fmt.Sprintf(`{"heartbeat_interval":%d}`, options.heartbeatIntervalMs)),
+		})
+		fake.conns <- conn
+
+		for {
+			payload := discordbot.GatewayPayload{}
+			if err := conn.ReadJSON(&payload); err != nil {
+				return
+			}
+
+			if payload.Opcode == discordbot.OpcodeHeartbeat && options.ackHeartbeats {
+				fake.ack(conn)
+			}
+			fake.received <- payload
+		}
+	}))
+
+	return fake
+}
+
+func (f *fakeGateway) write(conn *websocket.Conn, payload discordbot.GatewayPayload) error {
+	f.writeMutex.Lock()
+	defer f.writeMutex.Unlock()
+	return conn.WriteJSON(payload)
+}
+
+// Acks a heartbeat unless acks were stopped.
+func (f *fakeGateway) ack(conn *websocket.Conn) {
+	f.writeMutex.Lock()
+	defer f.writeMutex.Unlock()
+
+	if f.acksStopped {
+		f.unacked <- time.Now()
+		return
+	}
+	conn.WriteJSON(discordbot.GatewayPayload{Opcode: discordbot.OpcodeHeartbeatACK})
+}
+
+// Makes the connection a zombie that no longer acks heartbeats.
+func (f *fakeGateway) stopAcks() {
+	f.writeMutex.Lock()
+	f.acksStopped = true
+	f.writeMutex.Unlock()
+}
+
+func (f *fakeGateway) url() string {
+	return "ws" + strings.TrimPrefix(f.server.URL, "http")
+}
+
+// Returns a connected gateway talking to the fake server.
+func (f *fakeGateway) connect(t *testing.T) *discordbot.DiscordGateway {
+	gateway := &discordbot.DiscordGateway{
+		DiscordClient: discordbot.DiscordClient{AuthToken: "test"},
+		GatewayInfo:   discordbot.GatewayInfo{Url: f.url()},
+	}
+
+	if err := gateway.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	f.conn = <-f.conns
+
+	return gateway
+}
+
+// Sends a dispatch event to the connected client.
+func (f *fakeGateway) dispatch(t *testing.T, event string, data interface{}) {
+	eventData, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.sequence++
+	sequence := f.sequence
+	err = f.write(f.conn, discordbot.GatewayPayload{
+		Opcode:         discordbot.OpcodeDispatch,
+		EventName:      event,
+		EventData:      eventData,
+		SequenceNumber: &sequence,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Waits for the next payload with the given opcode, skipping any others.
+func (f *fakeGateway) expect(t *testing.T, opcode int) discordbot.GatewayPayload {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case payload := <-f.received:
+			if payload.Opcode == opcode {
+				return payload
+			}
+		case <-timeout:
+			t.Fatalf("did not receive opcode [%d]", opcode)
+		}
+	}
+}
+
+// Asks the client to send a heartbeat immediately and returns the sequence it carries.
+func (f *fakeGateway) heartbeat(t *testing.T) int {
+	if err := f.write(f.conn, discordbot.GatewayPayload{Opcode: discordbot.OpcodeHeartbeat}); err != nil {
+		t.Fatal(err)
+	}
+
+	var sequence *int
+	if err := json.Unmarshal(f.expect(t, discordbot.OpcodeHeartbeat).EventData, &sequence); err != nil {
+		t.Fatal(err)
+	}
+
+	if sequence == nil {
+		t.Fatal("heartbeat without a sequence")
+	}
+	return *sequence
+}
+
+const fakeUserId discordbot.Snowflake = 80351110224678912
+const fakeSessionId = "fake-session"
+
+// Identifies the gateway against the fake server, which responds with a ready event.
+func (f *fakeGateway) identify(t *testing.T, gateway *discordbot.DiscordGateway) {
+	done := make(chan error)
+	go func() {
+		_, err := gateway.Identify(nil)
+		done <- err
+	}()
+
+	f.expect(t, discordbot.OpcodeIdentify)
+	f.dispatch(t, discordbot.EventReady, map[string]interface{}{
+		"v":          6,
+		"user":       map[string]interface{}{"id": fakeUserId, "username": "bot", "discriminator": "0001"},
+		"session_id": fakeSessionId,
+	})
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeartbeatLatency(t *testing.T) {
+	fake := newFakeGatewayWithOptions(t, fakeGatewayOptions{heartbeatIntervalMs: 50, ackHeartbeats: true})
+	gateway := fake.connect(t)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		last, smoothed := gateway.Latency()
+		if last > 0 && smoothed > 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("latency not recorded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// An ack for a heartbeat requested by the server must not be taken as the ack of the next timed
+// heartbeat, or a connection that stops acking is only noticed an interval late.
+func TestRequestedHeartbeatAckIsNotReused(t *testing.T) {
+	const interval = 500 * time.Millisecond
+	fake := newFakeGatewayWithOptions(t, fakeGatewayOptions{heartbeatIntervalMs: 500, ackHeartbeats: true})
+	gateway := fake.connect(t)
+	fake.identify(t, gateway)
+
+	fake.heartbeat(t)
+	fake.stopAcks()
+
+	var unacked time.Time
+	select {
+	case unacked = <-fake.unacked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no heartbeat sent after acks stopped")
+	}
+
+	if last, _ := gateway.Latency(); last <= 0 || last > interval {
+		t.Errorf("unexpected latency [%v] for the requested heartbeat", last)
+	}
+
+	select {
+	case <-fake.conns:
+		if waited := time.Since(unacked); waited > interval*3/2 {
+			t.Errorf("zombie connection noticed [%v] after the unacknowledged heartbeat", waited)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("gateway did not reconnect after unacknowledged heartbeat")
+	}
+}
+
+func TestZombieConnectionResumes(t *testing.T) {
+	fake := newFakeGatewayWithOptions(t, fakeGatewayOptions{heartbeatIntervalMs: 200, ackHeartbeats: false})
+	gateway := fake.connect(t)
+	fake.identify(t, gateway)
+	fake.dispatch(t, discordbot.EventGuildCreate, map[string]interface{}{})
+
+	select {
+	case fake.conn = <-fake.conns:
+	case <-time.After(5 * time.Second):
+		t.Fatal("gateway did not reconnect after unacknowledged heartbeat")
+	}
+
+	resume := struct {
+		Token     string `json:"token"`
+		SessionId string `json:"session_id"`
+		Sequence  int    `json:"seq"`
+	}{}
+	if err := json.Unmarshal(fake.expect(t, discordbot.OpcodeResume).EventData, &resume); err != nil {
+		t.Fatal(err)
+	}
+
+	if resume.SessionId != fakeSessionId || resume.Sequence != 2 {
+		t.Errorf("unexpected resume: %+v", resume)
+	}
+}
+
+// Run with -race: dispatches are handled concurrently with heartbeats, listener registration and state reads.
+// Heartbeats are requested by the fake server rather than sent on a timer, so the test doesn't depend on timing.
+func TestConcurrentDispatchAndHeartbeats(t *testing.T) {
+	// Long enough that no heartbeat is sent on the timer during the test.
+	fake := newFakeGatewayWithOptions(t, fakeGatewayOptions{heartbeatIntervalMs: 24 * 60 * 60 * 1000})
+	gateway := fake.connect(t)
+	fake.identify(t, gateway)
+
+	if state := gateway.State(); state != discordbot.GatewayStateReady {
+		t.Fatalf("expected ready state, got [%v]", state)
+	}
+
+	const eventCount = 200
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < eventCount; i++ {
+			gateway.RegisterEventListener(discordbot.EventTypingStart, func(discordbot.GatewayPayload) {})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < eventCount; i++ {
+			gateway.State()
+			gateway.Latency()
+		}
+	}()
+
+	// Ready was sequence 1. Each heartbeat is awaited before the next is requested, since
+	// requested heartbeats are sent concurrently and could otherwise overtake each other.
+	last := 1
+	for i := 0; i < eventCount; i++ {
+		fake.dispatch(t, discordbot.EventTypingStart, map[string]string{})
+
+		if i%20 == 0 {
+			sequence := fake.heartbeat(t)
+			if sequence < last {
+				t.Fatalf("heartbeat sequence went backwards from [%d] to [%d]", last, sequence)
+			}
+			last = sequence
+		}
+	}
+	wg.Wait()
+
+	// Requested after the last dispatch, so it must carry the final sequence.
+	if sequence := fake.heartbeat(t); sequence != eventCount+1 {
+		t.Fatalf("expected heartbeat with final sequence [%d], got [%d]", eventCount+1, sequence)
+	}
+}
+
+func TestCloseStopsReconnecting(t *testing.T) {
+	fake := newFakeGateway(t)
+	gateway := fake.connect(t)
+
+	if err := gateway.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-fake.conns:
+		t.Fatal("gateway reconnected after close")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if state := gateway.State(); state != discordbot.GatewayStateDisconnected {
+		t.Errorf("expected disconnected state, got [%v]", state)
+	}
+}
+
+func TestCloseAfterFailedConnect(t *testing.T) {
+	gateway := &discordbot.DiscordGateway{GatewayInfo: discordbot.GatewayInfo{Url: "ws://127.0.0.1:1"}}
+
+	if err := gateway.Connect(); err == nil {
+		t.Fatal("expected connect to fail")
+	}
+
+	if err := gateway.Close(); err != nil {
+		t.Error(err)
+	}
+}
@@ -17,6 +17,7 @@ const (
 	EventGuildBanAdd              = "GUILD_BAN_ADD"
 	EventGuildBanRemove           = "GUILD_BAN_REMOVE"
 	EventGuildEmojisUpdate        = "GUILD_EMOJIS_UPDATE"
+	EventGuildStickersUpdate      = "GUILD_STICKERS_UPDATE"
 	EventGuildIntegrationsUpdate  = "GUILD_INTEGRATIONS_UPDATE"
 	EventGuildMemberAdd           = "GUILD_MEMBER_ADD"
 	EventGuildMemberRemove        = "GUILD_MEMBER_REMOVE"
@@ -39,3 +40,118 @@ const (
 	EventVoiceServerUpdate        = "VOICE_SERVER_UPDATE"
 	EventWebhooksUpdate           = "WEBHOOKS_UPDATE"
 )
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-member-add
+type GuildMemberAdd struct {
+	GuildMember
+	GuildId Snowflake `json:"guild_id"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-member-remove-guild-member-remove-event-fields
+type GuildMemberRemove struct {
+	GuildId Snowflake `json:"guild_id"`
+	User    User      `json:"user"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-member-update-guild-member-update-event-fields
+type GuildMemberUpdate struct {
+	GuildId      Snowflake   `json:"guild_id"`
+	Roles        []Snowflake `json:"roles"`
+	User         User        `json:"user"`
+	Nick         *string     `json:"nick"`
+	PremiumSince *Timestamp  `json:"premium_since,omitempty"`
+}
+
+// Sent for both GUILD_ROLE_CREATE and GUILD_ROLE_UPDATE.
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-role-create-guild-role-create-event-fields
+type GuildRoleUpdate struct {
+	GuildId Snowflake `json:"guild_id"`
+	Role    Role      `json:"role"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-role-delete-guild-role-delete-event-fields
+type GuildRoleDelete struct {
+	GuildId Snowflake `json:"guild_id"`
+	RoleId  Snowflake `json:"role_id"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#message-delete-message-delete-event-fields
+type MessageDelete struct {
+	Id        Snowflake  `json:"id"`
+	ChannelId Snowflake  `json:"channel_id"`
+	GuildId   *Snowflake `json:"guild_id,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#message-delete-bulk-message-delete-bulk-event-fields
+type MessageDeleteBulk struct {
+	Ids       []Snowflake `json:"ids"`
+	ChannelId Snowflake   `json:"channel_id"`
+	GuildId   *Snowflake  `json:"guild_id,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#message-reaction-add-message-reaction-add-event-fields
+type MessageReactionAdd struct {
+	UserId    Snowflake  `json:"user_id"`
+	ChannelId Snowflake  `json:"channel_id"`
+	MessageId Snowflake  `json:"message_id"`
+	GuildId   *Snowflake `json:"guild_id,omitempty"`
+	// Only sent for reactions in guilds.
+	Member *GuildMember `json:"member,omitempty"`
+	// Only the ID, name and animated fields are sent.
+	Emoji Emoji `json:"emoji"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#message-reaction-remove-message-reaction-remove-event-fields
+type MessageReactionRemove struct {
+	UserId    Snowflake  `json:"user_id"`
+	ChannelId Snowflake  `json:"channel_id"`
+	MessageId Snowflake  `json:"message_id"`
+	GuildId   *Snowflake `json:"guild_id,omitempty"`
+	Emoji     Emoji      `json:"emoji"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#message-reaction-remove-all-message-reaction-remove-all-event-fields
+type MessageReactionRemoveAll struct {
+	ChannelId Snowflake  `json:"channel_id"`
+	MessageId Snowflake  `json:"message_id"`
+	GuildId   *Snowflake `json:"guild_id,omitempty"`
+}
+
+// Not sent when a pinned message is deleted.
+// Reference: https://discordapp.com/developers/docs/topics/gateway#channel-pins-update-channel-pins-update-event-fields
+type ChannelPinsUpdate struct {
+	GuildId          *Snowflake `json:"guild_id,omitempty"`
+	ChannelId        Snowflake  `json:"channel_id"`
+	LastPinTimestamp *Timestamp `json:"last_pin_timestamp,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#typing-start-typing-start-event-fields
+type TypingStart struct {
+	ChannelId Snowflake  `json:"channel_id"`
+	GuildId   *Snowflake `json:"guild_id,omitempty"`
+	UserId    Snowflake  `json:"user_id"`
+	// Unix time in seconds.
+	Timestamp int64        `json:"timestamp"`
+	Member    *GuildMember `json:"member,omitempty"`
+}
+
+// Sent for both GUILD_BAN_ADD and GUILD_BAN_REMOVE.
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-ban-add-guild-ban-add-event-fields
+type GuildBanUpdate struct {
+	GuildId Snowflake `json:"guild_id"`
+	User    User      `json:"user"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-emojis-update-guild-emojis-update-event-fields
+type GuildEmojisUpdate struct {
+	GuildId Snowflake `json:"guild_id"`
+	// All of the guild's emojis.
+	Emojis []Emoji `json:"emojis"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-stickers-update-guild-stickers-update-event-fields
+type GuildStickersUpdate struct {
+	GuildId Snowflake `json:"guild_id"`
+	// All of the guild's stickers.
+	Stickers []Sticker `json:"stickers"`
+}
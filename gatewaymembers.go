@@ -0,0 +1,164 @@
+package discordbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#request-guild-members-guild-request-members-structure
+type gatewayRequestGuildMembers struct {
+	GuildId   Snowflake   `json:"guild_id"`
+	Query     *string     `json:"query,omitempty"`
+	Limit     int         `json:"limit"`
+	Presences bool        `json:"presences,omitempty"`
+	UserIds   []Snowflake `json:"user_ids,omitempty"`
+	Nonce     string      `json:"nonce"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#guild-members-chunk-guild-members-chunk-event-fields
+type gatewayGuildMembersChunk struct {
+	GuildId    Snowflake     `json:"guild_id"`
+	Members    []GuildMember `json:"members"`
+	ChunkIndex int           `json:"chunk_index"`
+	ChunkCount int           `json:"chunk_count"`
+	NotFound   []Snowflake   `json:"not_found,omitempty"`
+	Presences  []Presence    `json:"presences,omitempty"`
+	Nonce      string        `json:"nonce,omitempty"`
+}
+
+// Guild member requests waiting on chunks, keyed by nonce.
+type guildMemberRequests struct {
+	mutex     sync.Mutex
+	pending   map[string]*guildMemberRequest
+	lastNonce uint64
+}
+
+type guildMemberRequest struct {
+	chunks chan gatewayGuildMembersChunk
+	// Closed once the requester stops waiting.
+	done chan struct{}
+}
+
+// How long to wait for all member chunks if the context has no earlier deadline.
+const requestGuildMembersTimeout = time.Duration(30) * time.Second
+
+func newGuildMemberRequests() *guildMemberRequests {
+	return &guildMemberRequests{pending: make(map[string]*guildMemberRequest)}
+}
+
+func (r *guildMemberRequests) add() (nonce string, request *guildMemberRequest) {
+	nonce = strconv.FormatUint(atomic.AddUint64(&r.lastNonce, 1), 10)
+	request = &guildMemberRequest{
+		chunks: make(chan gatewayGuildMembersChunk),
+		done:   make(chan struct{}),
+	}
+
+	r.mutex.Lock()
+	r.pending[nonce] = request
+	r.mutex.Unlock()
+	return
+}
+
+func (r *guildMemberRequests) remove(nonce string) {
+	r.mutex.Lock()
+	if request, ok := r.pending[nonce]; ok {
+		close(request.done)
+		delete(r.pending, nonce)
+	}
+	r.mutex.Unlock()
+}
+
+// Called on GUILD_MEMBERS_CHUNK. Forwards the chunk to the request with the matching nonce.
+func (r *guildMemberRequests) chunkRecv(payload GatewayPayload) {
+	chunk := gatewayGuildMembersChunk{}
+	if err := json.Unmarshal(payload.EventData, &chunk); err != nil {
+		log.Print("Failed to parse guild members chunk: ", err)
+		return
+	}
+
+	r.mutex.Lock()
+	request, ok := r.pending[chunk.Nonce]
+	r.mutex.Unlock()
+
+	if !ok {
+		log.Printf("Ignoring guild members chunk with unknown nonce [%s].", chunk.Nonce)
+		return
+	}
+
+	select {
+	case request.chunks <- chunk:
+	case <-request.done:
+	}
+}
+
+// Requests members of a guild over the gateway and waits for every chunk of the response.
+// If userIds is non-empty the members with those IDs are requested and query is ignored.
+// A limit of 0 with an empty query requests all members.
+// Reference: https://discordapp.com/developers/docs/topics/gateway#request-guild-members
+func (g *DiscordGateway) RequestGuildMembers(
+	ctx context.Context, guildId Snowflake, query string, limit int, presences bool, userIds []Snowflake,
+) (members []GuildMember, err error) {
+
+	if err = g.checkConnected("request guild members"); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestGuildMembersTimeout)
+	defer cancel()
+
+	nonce, request := g.memberRequests.add()
+	defer g.memberRequests.remove(nonce)
+
+	membersRequest := gatewayRequestGuildMembers{
+		GuildId:   guildId,
+		Limit:     limit,
+		Presences: presences,
+		UserIds:   userIds,
+		Nonce:     nonce,
+	}
+
+	if len(userIds) == 0 {
+		membersRequest.Query = &query
+	}
+
+	var requestJsonBytes json.RawMessage
+	requestJsonBytes, err = json.Marshal(&membersRequest)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal guild members request: %v", err)
+	}
+
+	err = g.SendPayload(&GatewayPayload{
+		Opcode:    OpcodeRequestGuildMembers,
+		EventData: requestJsonBytes,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to send guild members request: %v", err)
+	}
+
+	// Chunk listeners run concurrently, so chunks may arrive in any order.
+	received := make(map[int]bool)
+	for {
+		select {
+		case chunk := <-request.chunks:
+			if !received[chunk.ChunkIndex] {
+				received[chunk.ChunkIndex] = true
+				members = append(members, chunk.Members...)
+			}
+
+			if len(received) >= chunk.ChunkCount {
+				return members, nil
+			}
+		case <-ctx.Done():
+			return nil, fmt.Errorf(
+				"received [%d] guild member chunks before timeout: %v", len(received), ctx.Err())
+		}
+	}
+}
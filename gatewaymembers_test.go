@@ -0,0 +1,85 @@
+package discordbot_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestRequestGuildMembers(t *testing.T) {
+	fake := newFakeGateway(t)
+	gateway := fake.connect(t)
+
+	type result struct {
+		members []discordbot.GuildMember
+		err     error
+	}
+	done := make(chan result)
+	go func() {
+		members, err := gateway.RequestGuildMembers(context.Background(), 41771983423143937, "", 0, false, nil)
+		done <- result{members, err}
+	}()
+
+	payload := fake.expect(t, discordbot.OpcodeRequestGuildMembers)
+	request := struct {
+		GuildId string `json:"guild_id"`
+		Nonce   string `json:"nonce"`
+	}{}
+	if err := json.Unmarshal(payload.EventData, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if request.GuildId != "41771983423143937" || request.Nonce == "" {
+		t.Fatalf("unexpected request: %s", payload.EventData)
+	}
+
+	// A chunk for some other request must be ignored.
+	fake.dispatch(t, discordbot.EventGuildMembersChunk, map[string]interface{}{
+		"guild_id": request.GuildId, "chunk_index": 0, "chunk_count": 1, "nonce": "other",
+		"members": []map[string]interface{}{{"user": map[string]string{"id": "0"}}},
+	})
+
+	for _, index := range []int{1, 0} {
+		fake.dispatch(t, discordbot.EventGuildMembersChunk, map[string]interface{}{
+			"guild_id": request.GuildId, "chunk_index": index, "chunk_count": 2, "nonce": request.Nonce,
+			"members": []map[string]interface{}{{"user": map[string]interface{}{"id": strconv.Itoa(index + 1)}}},
+		})
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		if len(r.members) != 2 {
+			t.Errorf("expected 2 members, got %+v", r.members)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request did not complete")
+	}
+}
+
+func TestRequestGuildMembersTimeout(t *testing.T) {
+	fake := newFakeGateway(t)
+	gateway := fake.connect(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := gateway.RequestGuildMembers(ctx, 41771983423143937, "", 0, false, []discordbot.Snowflake{1})
+	if err == nil {
+		t.Error("expected timeout error")
+	}
+}
+
+func TestRequestGuildMembersBeforeConnect(t *testing.T) {
+	gateway := &discordbot.DiscordGateway{}
+
+	if _, err := gateway.RequestGuildMembers(context.Background(), 1, "", 0, false, nil); err == nil {
+		t.Error("expected an error when not connected")
+	}
+}
@@ -0,0 +1,173 @@
+package discordbot
+
+import (
+	"sync"
+)
+
+// Connection state of a gateway.
+type GatewayState int
+
+const (
+	// Not connected, Connect has not been called.
+	GatewayStateDisconnected GatewayState = iota
+	// Dialing the gateway and waiting for hello.
+	GatewayStateConnecting
+	// Connected and heartbeating, but no session has been started.
+	GatewayStateConnected
+	// Identify sent, waiting for ready.
+	GatewayStateIdentifying
+	// Resume sent, waiting for resumed.
+	GatewayStateResuming
+	// Session is active and receiving events.
+	GatewayStateReady
+	// Replacing a failed connection.
+	GatewayStateReconnecting
+)
+
+func (s GatewayState) String() string {
+	switch s {
+	case GatewayStateDisconnected:
+		return "disconnected"
+	case GatewayStateConnecting:
+		return "connecting"
+	case GatewayStateConnected:
+		return "connected"
+	case GatewayStateIdentifying:
+		return "identifying"
+	case GatewayStateResuming:
+		return "resuming"
+	case GatewayStateReady:
+		return "ready"
+	case GatewayStateReconnecting:
+		return "reconnecting"
+	}
+	return "unknown"
+}
+
+// Session state shared by the read loop, event listeners, heartbeat and reconnects.
+// The zero value is a disconnected gateway with no session.
+type gatewaySession struct {
+	mutex sync.RWMutex
+	state GatewayState
+
+	id     string
+	userId Snowflake
+	// Gateway URL to use when resuming, if the ready event provided one.
+	resumeUrl string
+
+	sequence    int
+	hasSequence bool
+
+	// Set once Identify has been called, so a new session can be started if the old one is lost.
+	identified     bool
+	identifyStatus *GatewayStatusUpdate
+}
+
+func (s *gatewaySession) getState() GatewayState {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.state
+}
+
+func (s *gatewaySession) setState(state GatewayState) {
+	s.mutex.Lock()
+	s.state = state
+	s.mutex.Unlock()
+}
+
+// Records a sequence number from a dispatch. Sequence numbers only move forward, so one from an
+// older payload handled late is ignored.
+func (s *gatewaySession) advanceSequence(sequence *int) {
+	if sequence == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.hasSequence && *sequence <= s.sequence {
+		return
+	}
+
+	s.sequence = *sequence
+	s.hasSequence = true
+}
+
+// Returns the last sequence number received, or nil if none has been.
+func (s *gatewaySession) lastSequence() *int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if !s.hasSequence {
+		return nil
+	}
+
+	sequence := s.sequence
+	return &sequence
+}
+
+// Starts a new session from a ready event. The ready event's own sequence number has
+// already been recorded by the time it is handled, so the sequence is left alone.
+func (s *gatewaySession) start(id string, userId Snowflake, resumeUrl string) {
+	s.mutex.Lock()
+	s.id = id
+	s.userId = userId
+	s.resumeUrl = resumeUrl
+	s.state = GatewayStateReady
+	s.mutex.Unlock()
+}
+
+// Sequence numbers restart with each new session, so this is called before identifying.
+func (s *gatewaySession) resetSequence() {
+	s.mutex.Lock()
+	s.sequence = 0
+	s.hasSequence = false
+	s.mutex.Unlock()
+}
+
+// Forgets the session after it has been invalidated.
+func (s *gatewaySession) clear() {
+	s.mutex.Lock()
+	s.id = ""
+	s.resumeUrl = ""
+	s.sequence = 0
+	s.hasSequence = false
+	s.mutex.Unlock()
+}
+
+// Returns what's needed to resume the session. ok is false if there is no session to resume.
+func (s *gatewaySession) resumeInfo() (id string, sequence *int, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.hasSequence {
+		last := s.sequence
+		sequence = &last
+	}
+	return s.id, sequence, s.id != ""
+}
+
+func (s *gatewaySession) getResumeUrl() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.resumeUrl
+}
+
+func (s *gatewaySession) getUserId() (userId Snowflake, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.userId, s.userId != 0
+}
+
+func (s *gatewaySession) setIdentify(status *GatewayStatusUpdate) {
+	s.mutex.Lock()
+	s.identified = true
+	s.identifyStatus = status
+	s.mutex.Unlock()
+}
+
+func (s *gatewaySession) getIdentify() (status *GatewayStatusUpdate, identified bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.identifyStatus, s.identified
+}
@@ -1,13 +1,398 @@
 package discordbot
 
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const guildsEndpoint = "/guilds"
+
+// Guild sent in place of a full guild in READY, and for guilds affected by an outage.
 // Reference:
-// https://discordapp.com/developers/docs/resources/guild#guild-embed-object-guild-embed-structure
+// https://discordapp.com/developers/docs/resources/guild#unavailable-guild-object
 type UnavailableGuild struct {
-	Enabled   bool    `json:"enabled"`
-	ChannelId *string `json:"channel_id"`
+	Id          Snowflake `json:"id"`
+	Unavailable bool      `json:"unavailable"`
 }
 
-// TODO: add remaining fields
+// Reference:
+// https://discordapp.com/developers/docs/resources/guild#guild-embed-object-guild-embed-structure
+type GuildEmbed struct {
+	Enabled   bool       `json:"enabled"`
+	ChannelId *Snowflake `json:"channel_id"`
+}
+
+// The fields from JoinedAt to Presences are only sent in GUILD_CREATE.
+// Reference:
+// https://discordapp.com/developers/docs/resources/guild#guild-object-guild-structure
 type Guild struct {
-	Channels *[]Channel `json:"channels"`
+	Id     Snowflake `json:"id"`
+	Name   string    `json:"name"`
+	Icon   *string   `json:"icon"`
+	Splash *string   `json:"splash"`
+	// Only sent when listing the current user's guilds.
+	Owner   *bool     `json:"owner,omitempty"`
+	OwnerId Snowflake `json:"owner_id"`
+	// Only sent when listing the current user's guilds.
+	Permissions                 *Permissions  `json:"permissions,omitempty"`
+	Region                      string        `json:"region"`
+	AfkChannelId                *Snowflake    `json:"afk_channel_id"`
+	AfkTimeout                  int           `json:"afk_timeout"`
+	EmbedEnabled                *bool         `json:"embed_enabled,omitempty"`
+	EmbedChannelId              *Snowflake    `json:"embed_channel_id,omitempty"`
+	VerificationLevel           int           `json:"verification_level"`
+	DefaultMessageNotifications int           `json:"default_message_notifications"`
+	ExplicitContentFilter       int           `json:"explicit_content_filter"`
+	Roles                       []Role        `json:"roles"`
+	Emojis                      []Emoji       `json:"emojis"`
+	Stickers                    []Sticker     `json:"stickers,omitempty"`
+	Features                    []string      `json:"features"`
+	MfaLevel                    int           `json:"mfa_level"`
+	ApplicationId               *Snowflake    `json:"application_id"`
+	WidgetEnabled               *bool         `json:"widget_enabled,omitempty"`
+	WidgetChannelId             *Snowflake    `json:"widget_channel_id,omitempty"`
+	SystemChannelId             *Snowflake    `json:"system_channel_id"`
+	MaxPresences                *int          `json:"max_presences,omitempty"`
+	MaxMembers                  *int          `json:"max_members,omitempty"`
+	VanityUrlCode               *string       `json:"vanity_url_code"`
+	Description                 *string       `json:"description"`
+	Banner                      *string       `json:"banner"`
+	PremiumTier                 int           `json:"premium_tier"`
+	PremiumSubscriptionCount    *int          `json:"premium_subscription_count,omitempty"`
+	PreferredLocale             string        `json:"preferred_locale"`
+	JoinedAt                    *Timestamp    `json:"joined_at,omitempty"`
+	Large                       *bool         `json:"large,omitempty"`
+	Unavailable                 *bool         `json:"unavailable,omitempty"`
+	MemberCount                 *int          `json:"member_count,omitempty"`
+	VoiceStates                 []VoiceState  `json:"voice_states,omitempty"`
+	Members                     []GuildMember `json:"members,omitempty"`
+	Channels                    *[]Channel    `json:"channels"`
+	Presences                   []Presence    `json:"presences,omitempty"`
+	// Only set by GetGuild with counts.
+	ApproximateMemberCount   *int `json:"approximate_member_count,omitempty"`
+	ApproximatePresenceCount *int `json:"approximate_presence_count,omitempty"`
+}
+
+// Reference:
+// https://discordapp.com/developers/docs/resources/guild#guild-object-verification-level
+const (
+	VerificationLevelNone     = 0
+	VerificationLevelLow      = 1
+	VerificationLevelMedium   = 2
+	VerificationLevelHigh     = 3
+	VerificationLevelVeryHigh = 4
+)
+
+// Reference:
+// https://discordapp.com/developers/docs/resources/guild#guild-object-default-message-notification-level
+const (
+	MessageNotificationsAllMessages  = 0
+	MessageNotificationsOnlyMentions = 1
+)
+
+// Reference:
+// https://discordapp.com/developers/docs/resources/guild#guild-object-explicit-content-filter-level
+const (
+	ExplicitContentFilterDisabled            = 0
+	ExplicitContentFilterMembersWithoutRoles = 1
+	ExplicitContentFilterAllMembers          = 2
+)
+
+// Reference:
+// https://discordapp.com/developers/docs/resources/guild#guild-object-mfa-level
+const (
+	MfaLevelNone     = 0
+	MfaLevelElevated = 1
+)
+
+// Reference:
+// https://discordapp.com/developers/docs/resources/guild#guild-member-object-guild-member-structure
+type GuildMember struct {
+	User         User        `json:"user"`
+	Nick         *string     `json:"nick,omitempty"`
+	Roles        []Snowflake `json:"roles"`
+	JoinedAt     Timestamp   `json:"joined_at"`
+	PremiumSince *Timestamp  `json:"premium_since,omitempty"`
+	Deaf         bool        `json:"deaf"`
+	Mute         bool        `json:"mute"`
+	// Set while the member is timed out.
+	CommunicationDisabledUntil *Timestamp `json:"communication_disabled_until,omitempty"`
+}
+
+// Reference:
+// https://discordapp.com/developers/docs/topics/permissions#role-object-role-structure
+type Role struct {
+	Id    Snowflake `json:"id"`
+	Name  string    `json:"name"`
+	Color Color     `json:"color"`
+	// Shown separately in the member list.
+	Hoist       bool        `json:"hoist"`
+	Position    int         `json:"position"`
+	Permissions Permissions `json:"permissions"`
+	// Managed by an integration, e.g. a bot's own role.
+	Managed     bool `json:"managed"`
+	Mentionable bool `json:"mentionable"`
+}
+
+// Reference:
+// https://discordapp.com/developers/docs/resources/emoji#emoji-object-emoji-structure
+type Emoji struct {
+	// Null for unicode emoji.
+	Id            *Snowflake  `json:"id"`
+	Name          string      `json:"name"`
+	Roles         []Snowflake `json:"roles,omitempty"`
+	User          *User       `json:"user,omitempty"`
+	RequireColons *bool       `json:"require_colons,omitempty"`
+	Managed       *bool       `json:"managed,omitempty"`
+	Animated      *bool       `json:"animated,omitempty"`
+}
+
+// Public information about a lurkable guild, available without being a member.
+// Reference: https://discordapp.com/developers/docs/resources/guild#guild-preview-object
+type GuildPreview struct {
+	Id                       Snowflake `json:"id"`
+	Name                     string    `json:"name"`
+	Icon                     *string   `json:"icon"`
+	Splash                   *string   `json:"splash"`
+	DiscoverySplash          *string   `json:"discovery_splash"`
+	Emojis                   []Emoji   `json:"emojis"`
+	Features                 []string  `json:"features"`
+	ApproximateMemberCount   int       `json:"approximate_member_count"`
+	ApproximatePresenceCount int       `json:"approximate_presence_count"`
+	Description              *string   `json:"description"`
+}
+
+// Gets a guild. With counts, the approximate member and presence counts are set.
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild
+func (client *DiscordClient) GetGuild(guildId Snowflake, withCounts bool) (guild Guild, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s", guildsEndpoint, guildId),
+		query:    url.Values{"with_counts": {strconv.FormatBool(withCounts)}},
+		result:   &guild,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-preview
+func (client *DiscordClient) GetGuildPreview(guildId Snowflake) (preview GuildPreview, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/preview", guildsEndpoint, guildId),
+		result:   &preview,
+	})
+	return
+}
+
+// Changes to a guild's settings.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-json-params
+type GuildPatch struct {
+	fields jsonPatch
+}
+
+func (p *GuildPatch) SetName(name string) *GuildPatch {
+	p.fields.set("name", name)
+	return p
+}
+
+// Voice region ID, see GetGuildVoiceRegions.
+func (p *GuildPatch) SetRegion(region string) *GuildPatch {
+	p.fields.set("region", region)
+	return p
+}
+
+// One of the VerificationLevel constants.
+func (p *GuildPatch) SetVerificationLevel(level int) *GuildPatch {
+	p.fields.set("verification_level", level)
+	return p
+}
+
+// One of the MessageNotifications constants.
+func (p *GuildPatch) SetDefaultMessageNotifications(level int) *GuildPatch {
+	p.fields.set("default_message_notifications", level)
+	return p
+}
+
+// One of the ExplicitContentFilter constants.
+func (p *GuildPatch) SetExplicitContentFilter(level int) *GuildPatch {
+	p.fields.set("explicit_content_filter", level)
+	return p
+}
+
+// Nil removes the AFK channel.
+func (p *GuildPatch) SetAfkChannelId(channelId *Snowflake) *GuildPatch {
+	p.fields.set("afk_channel_id", channelId)
+	return p
+}
+
+// Seconds, one of 60, 300, 900, 1800 or 3600.
+func (p *GuildPatch) SetAfkTimeout(seconds int) *GuildPatch {
+	p.fields.set("afk_timeout", seconds)
+	return p
+}
+
+// Image data URI. Nil removes the icon.
+func (p *GuildPatch) SetIcon(icon *string) *GuildPatch {
+	p.fields.set("icon", icon)
+	return p
+}
+
+// Transfers ownership. The current user must be the owner.
+func (p *GuildPatch) SetOwnerId(ownerId Snowflake) *GuildPatch {
+	p.fields.set("owner_id", ownerId)
+	return p
+}
+
+// Image data URI, for guilds with the INVITE_SPLASH feature. Nil removes the splash.
+func (p *GuildPatch) SetSplash(splash *string) *GuildPatch {
+	p.fields.set("splash", splash)
+	return p
+}
+
+// Image data URI, for guilds with the BANNER feature. Nil removes the banner.
+func (p *GuildPatch) SetBanner(banner *string) *GuildPatch {
+	p.fields.set("banner", banner)
+	return p
+}
+
+// Nil disables system messages.
+func (p *GuildPatch) SetSystemChannelId(channelId *Snowflake) *GuildPatch {
+	p.fields.set("system_channel_id", channelId)
+	return p
+}
+
+func (p GuildPatch) MarshalJSON() ([]byte, error) {
+	return p.fields.marshal()
+}
+
+// Updates a guild's settings.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild
+func (client *DiscordClient) ModifyGuild(guildId Snowflake, patch GuildPatch, reason string) (guild Guild, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s", guildsEndpoint, guildId),
+		body:     patch,
+		reason:   reason,
+		result:   &guild,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-channels
+func (client *DiscordClient) GetGuildChannels(guildId Snowflake) (channels []Channel, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/channels", guildsEndpoint, guildId),
+		result:   &channels,
+	})
+	return
+}
+
+// Settings of a new guild channel. Only the name is required.
+// Reference: https://discordapp.com/developers/docs/resources/guild#create-guild-channel-json-params
+type GuildChannelOptions struct {
+	Name string `json:"name"`
+	// One of the ChannelType constants, defaults to a text channel.
+	Type  int    `json:"type"`
+	Topic string `json:"topic,omitempty"`
+	// Voice channels only.
+	Bitrate   int `json:"bitrate,omitempty"`
+	UserLimit int `json:"user_limit,omitempty"`
+	// Text channels only.
+	RateLimitPerUser     int         `json:"rate_limit_per_user,omitempty"`
+	Position             *int        `json:"position,omitempty"`
+	PermissionOverwrites []Overwrite `json:"permission_overwrites,omitempty"`
+	ParentId             *Snowflake  `json:"parent_id,omitempty"`
+	Nsfw                 bool        `json:"nsfw,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#create-guild-channel
+func (client *DiscordClient) CreateGuildChannel(
+	guildId Snowflake, options GuildChannelOptions, reason string,
+) (channel Channel, err error) {
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPost,
+		endpoint: fmt.Sprintf("%s/%s/channels", guildsEndpoint, guildId),
+		body:     &options,
+		reason:   reason,
+		result:   &channel,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-channel-positions-json-params
+type ChannelPosition struct {
+	Id       Snowflake `json:"id"`
+	Position int       `json:"position"`
+}
+
+// Moves channels. Only the channels being moved need to be given.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-channel-positions
+func (client *DiscordClient) ModifyGuildChannelPositions(guildId Snowflake, positions []ChannelPosition, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s/channels", guildsEndpoint, guildId),
+		body:     positions,
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-prune-count
+type guildPruneResult struct {
+	// Null when the count wasn't computed.
+	Pruned *int `json:"pruned"`
+}
+
+// Number of members that would be removed by a prune of members inactive for the given
+// number of days, 1-30.
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-prune-count
+func (client *DiscordClient) GetGuildPruneCount(guildId Snowflake, days int) (pruned int, err error) {
+	result := guildPruneResult{}
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/prune", guildsEndpoint, guildId),
+		query:    url.Values{"days": {strconv.Itoa(days)}},
+		result:   &result,
+	})
+
+	if err == nil && result.Pruned != nil {
+		pruned = *result.Pruned
+	}
+	return
+}
+
+// Removes members inactive for the given number of days, 1-30. Counting the removed members is
+// slow for large guilds, so without computeCount the returned count is nil.
+// Reference: https://discordapp.com/developers/docs/resources/guild#begin-guild-prune
+func (client *DiscordClient) BeginGuildPrune(
+	guildId Snowflake, days int, computeCount bool, reason string,
+) (pruned *int, err error) {
+
+	result := guildPruneResult{}
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPost,
+		endpoint: fmt.Sprintf("%s/%s/prune", guildsEndpoint, guildId),
+		query: url.Values{
+			"days":                {strconv.Itoa(days)},
+			"compute_prune_count": {strconv.FormatBool(computeCount)},
+		},
+		reason: reason,
+		result: &result,
+	})
+	return result.Pruned, err
+}
+
+// Voice regions for the guild. VIP regions are only listed for VIP guilds.
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-voice-regions
+func (client *DiscordClient) GetGuildVoiceRegions(guildId Snowflake) (regions []VoiceRegion, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/regions", guildsEndpoint, guildId),
+		result:   &regions,
+	})
+	return
 }
@@ -0,0 +1,172 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+const guildCreatePayload = `{
+	"id": "41771983423143937",
+	"name": "Discord Developers",
+	"icon": "86e39f7ae3307e811784e2ffd11a7310",
+	"splash": null,
+	"owner_id": "80351110224678912",
+	"region": "us-east",
+	"afk_channel_id": null,
+	"afk_timeout": 300,
+	"verification_level": 1,
+	"default_message_notifications": 1,
+	"explicit_content_filter": 2,
+	"roles": [{"id": "41771983423143937", "name": "@everyone", "color": 0, "hoist": false,
+		"position": 0, "permissions": 104324161, "managed": false, "mentionable": false}],
+	"emojis": [{"id": "41771983429993937", "name": "LUL", "roles": [], "require_colons": true,
+		"managed": false, "animated": false}],
+	"features": ["INVITE_SPLASH"],
+	"mfa_level": 0,
+	"application_id": null,
+	"system_channel_id": "41771983423143937",
+	"joined_at": "2017-07-11T17:27:07.299000+00:00",
+	"large": false,
+	"unavailable": false,
+	"member_count": 1,
+	"voice_states": [{"channel_id": "127121515262115840", "user_id": "80351110224678912",
+		"session_id": "90326bd25d71d39b9ef95b299e3872ff", "deaf": false, "mute": false,
+		"self_deaf": false, "self_mute": true, "suppress": false}],
+	"members": [{"user": {"id": "80351110224678912", "username": "Nelly", "discriminator": "1337"},
+		"nick": null, "roles": [], "joined_at": "2015-04-26T06:26:56.936000+00:00", "deaf": false, "mute": false}],
+	"channels": [{"id": "41771983423143937", "type": 0, "name": "general"}],
+	"presences": [{"user": {"id": "80351110224678912"}, "game": {"name": "Rocket League", "type": 0},
+		"status": "online"}]
+}`
+
+func TestGuildCreateDecode(t *testing.T) {
+	guild := discordbot.Guild{}
+	if err := json.Unmarshal([]byte(guildCreatePayload), &guild); err != nil {
+		t.Fatal(err)
+	}
+
+	if guild.Id != 41771983423143937 || guild.Name != "Discord Developers" || guild.OwnerId != 80351110224678912 {
+		t.Errorf("unexpected guild: %+v", guild)
+	}
+
+	if len(guild.Roles) != 1 || guild.Roles[0].Permissions != 104324161 {
+		t.Errorf("unexpected roles: %+v", guild.Roles)
+	}
+
+	if len(guild.Emojis) != 1 || guild.Emojis[0].Id == nil || guild.Emojis[0].Name != "LUL" {
+		t.Errorf("unexpected emojis: %+v", guild.Emojis)
+	}
+
+	if guild.MemberCount == nil || *guild.MemberCount != 1 || len(guild.Members) != 1 ||
+		guild.Members[0].User.Username != "Nelly" {
+		t.Errorf("unexpected members: %+v", guild.Members)
+	}
+
+	if len(guild.VoiceStates) != 1 || !guild.VoiceStates[0].SelfMute {
+		t.Errorf("unexpected voice states: %+v", guild.VoiceStates)
+	}
+
+	if len(guild.Presences) != 1 || guild.Presences[0].Game == nil || guild.Presences[0].Status != discordbot.StatusOnline {
+		t.Errorf("unexpected presences: %+v", guild.Presences)
+	}
+
+	if guild.Channels == nil || len(*guild.Channels) != 1 || guild.JoinedAt == nil {
+		t.Errorf("unexpected channels or join time: %+v", guild)
+	}
+}
+
+func TestUnavailableGuildDecode(t *testing.T) {
+	guild := discordbot.UnavailableGuild{}
+	if err := json.Unmarshal([]byte(`{"id": "41771983423143937", "unavailable": true}`), &guild); err != nil {
+		t.Fatal(err)
+	}
+
+	if guild.Id != 41771983423143937 || !guild.Unavailable {
+		t.Errorf("unexpected unavailable guild: %+v", guild)
+	}
+}
+
+func TestGetGuildWithCounts(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		guild := map[string]interface{}{}
+		json.Unmarshal([]byte(guildCreatePayload), &guild)
+		guild["approximate_member_count"] = 42
+		return http.StatusOK, guild
+	})
+
+	client := api.client(t)
+	guild, err := client.GetGuild(41771983423143937, true)
+
+	if err != nil || guild.ApproximateMemberCount == nil || *guild.ApproximateMemberCount != 42 {
+		t.Fatalf("unexpected guild %+v: %v", guild, err)
+	}
+
+	if request := api.single(t); request.Path != "/guilds/41771983423143937" || request.Query != "with_counts=true" {
+		t.Errorf("unexpected request %s?%s", request.Path, request.Query)
+	}
+}
+
+func TestModifyGuild(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, map[string]string{"id": "1", "name": "Renamed"}
+	})
+
+	patch := discordbot.GuildPatch{}
+	patch.SetName("Renamed").SetAfkChannelId(nil).SetVerificationLevel(discordbot.VerificationLevelHigh)
+
+	client := api.client(t)
+	if _, err := client.ModifyGuild(1, patch, "rebrand"); err != nil {
+		t.Fatal(err)
+	}
+
+	request := api.single(t)
+	if request.Method != http.MethodPatch || string(request.Body) != `{"afk_channel_id":null,"name":"Renamed","verification_level":3}` {
+		t.Errorf("unexpected request %s %s", request.Method, request.Body)
+	}
+}
+
+func TestGuildPrune(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		if request.Method == http.MethodGet {
+			return http.StatusOK, map[string]int{"pruned": 7}
+		}
+		return http.StatusOK, map[string]interface{}{"pruned": nil}
+	})
+
+	client := api.client(t)
+	count, err := client.GetGuildPruneCount(1, 30)
+
+	if err != nil || count != 7 {
+		t.Fatalf("unexpected prune count %d: %v", count, err)
+	}
+
+	pruned, err := client.BeginGuildPrune(1, 30, false, "")
+	if err != nil || pruned != nil {
+		t.Fatalf("expected no count without computing it, got %v: %v", pruned, err)
+	}
+
+	requests := api.received()
+	if requests[0].Query != "days=30" || requests[1].Query != "compute_prune_count=false&days=30" {
+		t.Errorf("unexpected queries [%s] and [%s]", requests[0].Query, requests[1].Query)
+	}
+}
+
+func TestModifyGuildChannelPositions(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNoContent, nil
+	})
+
+	client := api.client(t)
+	err := client.ModifyGuildChannelPositions(1, []discordbot.ChannelPosition{{Id: 2, Position: 0}, {Id: 3, Position: 1}}, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request := api.single(t); string(request.Body) != `[{"id":"2","position":0},{"id":"3","position":1}]` {
+		t.Errorf("unexpected body %s", request.Body)
+	}
+}
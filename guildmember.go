@@ -0,0 +1,298 @@
+package discordbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-member
+func (client *DiscordClient) GetGuildMember(guildId Snowflake, userId Snowflake) (member GuildMember, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/members/%s", guildsEndpoint, guildId, userId),
+		result:   &member,
+	})
+	return
+}
+
+// Page of a guild's members. Zero fields are unset.
+type ListGuildMembersQuery struct {
+	// Only members with a greater user ID. Set to the last member of the previous page to get the next one.
+	After Snowflake
+	// 1-1000, defaults to 1.
+	Limit int
+}
+
+const maxMembersPerRequest = 1000
+
+// Gets a page of a guild's members in user ID order. Use GuildMembers to walk all members.
+// Reference: https://discordapp.com/developers/docs/resources/guild#list-guild-members
+func (client *DiscordClient) ListGuildMembers(guildId Snowflake, query ListGuildMembersQuery) ([]GuildMember, error) {
+	return client.listGuildMembers(context.Background(), guildId, query)
+}
+
+func (client *DiscordClient) listGuildMembers(
+	ctx context.Context, guildId Snowflake, query ListGuildMembersQuery,
+) (members []GuildMember, err error) {
+
+	values := url.Values{}
+	if query.After != 0 {
+		values.Set("after", query.After.String())
+	}
+	if query.Limit != 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	err = client.do(ctx, restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/members", guildsEndpoint, guildId),
+		query:    values,
+		result:   &members,
+	})
+	return
+}
+
+// Iterates over all members of a guild, fetching up to 1000 at a time with ListGuildMembers.
+// Used like MessageHistory.
+type GuildMemberIterator struct {
+	client  *DiscordClient
+	ctx     context.Context
+	guildId Snowflake
+	limit   int
+
+	page    []GuildMember
+	current GuildMember
+	after   Snowflake
+	count   int
+	done    bool
+	err     error
+}
+
+// Creates an iterator over a guild's members in user ID order, stopping after limit members
+// unless it is 0. No requests are made until Next is called.
+func (client *DiscordClient) GuildMembers(ctx context.Context, guildId Snowflake, limit int) *GuildMemberIterator {
+	return &GuildMemberIterator{client: client, ctx: ctx, guildId: guildId, limit: limit}
+}
+
+// Advances to the next member, fetching another page if needed. Returns false after the last
+// member, or on error.
+func (m *GuildMemberIterator) Next() bool {
+	if m.limit > 0 && m.count >= m.limit {
+		return false
+	}
+
+	if len(m.page) == 0 && !m.done {
+		m.fetch()
+	}
+
+	if len(m.page) == 0 {
+		return false
+	}
+
+	m.current = m.page[0]
+	m.page = m.page[1:]
+	m.count++
+	return true
+}
+
+// Member the iterator is at.
+func (m *GuildMemberIterator) Member() GuildMember {
+	return m.current
+}
+
+// Error that stopped the iteration, if any.
+func (m *GuildMemberIterator) Err() error {
+	return m.err
+}
+
+func (m *GuildMemberIterator) fetch() {
+	query := ListGuildMembersQuery{After: m.after, Limit: maxMembersPerRequest}
+	if remaining := m.limit - m.count; m.limit > 0 && remaining < query.Limit {
+		query.Limit = remaining
+	}
+
+	members, err := m.client.listGuildMembers(m.ctx, m.guildId, query)
+
+	if err != nil {
+		m.err = err
+		m.done = true
+		return
+	}
+
+	if len(members) < query.Limit {
+		m.done = true
+	}
+
+	for _, member := range members {
+		if member.User.Id > m.after {
+			m.after = member.User.Id
+		}
+	}
+	m.page = members
+}
+
+// Gets up to limit members, 1-1000, whose username or nickname starts with the query.
+// Reference: https://discordapp.com/developers/docs/resources/guild#search-guild-members
+func (client *DiscordClient) SearchGuildMembers(guildId Snowflake, query string, limit int) (members []GuildMember, err error) {
+	values := url.Values{"query": {query}}
+	if limit != 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/members/search", guildsEndpoint, guildId),
+		query:    values,
+		result:   &members,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#add-guild-member-json-params
+type AddGuildMemberOptions struct {
+	// OAuth2 access token of the user, granted with the guilds.join scope.
+	AccessToken string `json:"access_token"`
+	// The fields below need the matching permissions.
+	Nick  string      `json:"nick,omitempty"`
+	Roles []Snowflake `json:"roles,omitempty"`
+	Mute  bool        `json:"mute,omitempty"`
+	Deaf  bool        `json:"deaf,omitempty"`
+}
+
+// Adds a user to the guild using an OAuth2 access token. The bot must be in the guild. Returns
+// false if the user was already a member, in which case the member isn't set.
+// Reference: https://discordapp.com/developers/docs/resources/guild#add-guild-member
+func (client *DiscordClient) AddGuildMember(
+	guildId Snowflake, userId Snowflake, options AddGuildMemberOptions, reason string,
+) (member GuildMember, added bool, err error) {
+
+	// Nothing is returned if the user was already a member.
+	var result *GuildMember
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPut,
+		endpoint: fmt.Sprintf("%s/%s/members/%s", guildsEndpoint, guildId, userId),
+		body:     &options,
+		reason:   reason,
+		result:   &result,
+	})
+
+	if err == nil && result != nil {
+		return *result, true, nil
+	}
+	return
+}
+
+// Changes to a guild member.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-member-json-params
+type GuildMemberPatch struct {
+	fields jsonPatch
+}
+
+// Nil removes the nickname.
+func (p *GuildMemberPatch) SetNick(nick *string) *GuildMemberPatch {
+	p.fields.set("nick", nick)
+	return p
+}
+
+// Replaces all of the member's roles.
+func (p *GuildMemberPatch) SetRoles(roleIds []Snowflake) *GuildMemberPatch {
+	if roleIds == nil {
+		roleIds = []Snowflake{}
+	}
+	p.fields.set("roles", roleIds)
+	return p
+}
+
+// Mutes the member in voice channels. Fails if the member isn't connected to voice.
+func (p *GuildMemberPatch) SetMute(mute bool) *GuildMemberPatch {
+	p.fields.set("mute", mute)
+	return p
+}
+
+// Deafens the member in voice channels. Fails if the member isn't connected to voice.
+func (p *GuildMemberPatch) SetDeaf(deaf bool) *GuildMemberPatch {
+	p.fields.set("deaf", deaf)
+	return p
+}
+
+// Moves the member to another voice channel. Nil disconnects the member from voice.
+func (p *GuildMemberPatch) SetChannelId(channelId *Snowflake) *GuildMemberPatch {
+	p.fields.set("channel_id", channelId)
+	return p
+}
+
+// Times the member out until the given time, at most 28 days away. Nil removes the timeout.
+func (p *GuildMemberPatch) SetTimeout(until *time.Time) *GuildMemberPatch {
+	if until == nil {
+		p.fields.set("communication_disabled_until", nil)
+	} else {
+		p.fields.set("communication_disabled_until", Timestamp{Time: *until})
+	}
+	return p
+}
+
+func (p GuildMemberPatch) MarshalJSON() ([]byte, error) {
+	return p.fields.marshal()
+}
+
+// Updates a guild member.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-member
+func (client *DiscordClient) ModifyGuildMember(
+	guildId Snowflake, userId Snowflake, patch GuildMemberPatch, reason string,
+) error {
+
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s/members/%s", guildsEndpoint, guildId, userId),
+		body:     patch,
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-current-user-nick-json-params
+type modifyCurrentMemberNick struct {
+	Nick *string `json:"nick"`
+}
+
+// Changes the current user's nickname in the guild. Nil removes it.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-current-user-nick
+func (client *DiscordClient) ModifyCurrentMemberNick(guildId Snowflake, nick *string, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s/members/@me/nick", guildsEndpoint, guildId),
+		body:     modifyCurrentMemberNick{Nick: nick},
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#add-guild-member-role
+func (client *DiscordClient) AddGuildMemberRole(guildId Snowflake, userId Snowflake, roleId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodPut,
+		endpoint: fmt.Sprintf("%s/%s/members/%s/roles/%s", guildsEndpoint, guildId, userId, roleId),
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#remove-guild-member-role
+func (client *DiscordClient) RemoveGuildMemberRole(guildId Snowflake, userId Snowflake, roleId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/members/%s/roles/%s", guildsEndpoint, guildId, userId, roleId),
+		reason:   reason,
+	})
+}
+
+// Kicks a member from the guild.
+// Reference: https://discordapp.com/developers/docs/resources/guild#remove-guild-member
+func (client *DiscordClient) RemoveGuildMember(guildId Snowflake, userId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/members/%s", guildsEndpoint, guildId, userId),
+		reason:   reason,
+	})
+}
@@ -0,0 +1,92 @@
+package discordbot_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestGuildMemberIterator(t *testing.T) {
+	const memberCount = 1500
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		query, _ := url.ParseQuery(request.Query)
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		after, _ := strconv.Atoi(query.Get("after"))
+
+		page := []map[string]interface{}{}
+		for id := after + 1; id <= memberCount && len(page) < limit; id++ {
+			page = append(page, map[string]interface{}{
+				"user": map[string]string{"id": strconv.Itoa(id)}, "roles": []string{},
+				"joined_at": "2015-04-26T06:26:56.936000+00:00",
+			})
+		}
+		return http.StatusOK, page
+	})
+
+	client := api.client(t)
+	members := client.GuildMembers(context.Background(), 1, 0)
+
+	count := 0
+	for members.Next() {
+		count++
+		if members.Member().User.Id != discordbot.Snowflake(count) {
+			t.Fatalf("member %d has ID %v", count, members.Member().User.Id)
+		}
+	}
+
+	if err := members.Err(); err != nil || count != memberCount {
+		t.Fatalf("expected %d members, got %d: %v", memberCount, count, err)
+	}
+
+	requests := api.received()
+	if len(requests) != 2 || requests[1].Query != "after=1000&limit=1000" {
+		t.Errorf("unexpected requests %+v", requests)
+	}
+}
+
+func TestModifyGuildMember(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNoContent, nil
+	})
+
+	until := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	patch := discordbot.GuildMemberPatch{}
+	patch.SetChannelId(nil).SetTimeout(&until).SetRoles(nil)
+
+	client := api.client(t)
+	if err := client.ModifyGuildMember(1, 2, patch, "spam"); err != nil {
+		t.Fatal(err)
+	}
+
+	request := api.single(t)
+	body := map[string]json.RawMessage{}
+	json.Unmarshal(request.Body, &body)
+
+	if request.Path != "/guilds/1/members/2" || string(body["channel_id"]) != "null" || string(body["roles"]) != "[]" ||
+		string(body["communication_disabled_until"]) != `"2026-01-02T03:04:05+00:00"` {
+		t.Errorf("unexpected request %s %s", request.Path, request.Body)
+	}
+}
+
+func TestAddGuildMemberAlreadyMember(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNoContent, nil
+	})
+
+	client := api.client(t)
+	_, added, err := client.AddGuildMember(1, 2, discordbot.AddGuildMemberOptions{AccessToken: "oauth"}, "")
+
+	if err != nil || added {
+		t.Errorf("expected an existing member not to be added, got %v: %v", added, err)
+	}
+
+	if request := api.single(t); request.Method != http.MethodPut || string(request.Body) != `{"access_token":"oauth"}` {
+		t.Errorf("unexpected request %s %s", request.Method, request.Body)
+	}
+}
@@ -1,79 +1,181 @@
 package discordbot
 
 import (
+	"fmt"
 	"log"
+	"math/rand"
+	"sync"
 	"time"
-
-	"github.com/gorilla/websocket"
 )
 
+// Connection that heartbeats are sent over, either the main gateway or a voice gateway.
+type heartbeatConn interface {
+	// Sends a single heartbeat payload.
+	sendHeartbeat() error
+	// Called when a heartbeat can't be sent or isn't acknowledged in time. The heartbeat stops afterwards.
+	heartbeatFailed(err error)
+}
+
 // Responsible for sending and receiving periodic discord heartbeats.
 type discordHeartbeat struct {
-	gateway       *DiscordGateway
-	interval      time.Duration
-	lastHeartbeat time.Time
+	conn     heartbeatConn
+	interval time.Duration
+	latency  *heartbeatLatency
 
-	// Returns the current sequence number.
-	getSequenceNum func() *int
-
-	// Synchronization channels for heartbeats and acks
+	// Signals the heartbeat loop that the outstanding heartbeat was acknowledged.
 	heartbeatAck chan bool
-	heartbeat    chan time.Time
+
+	// When the unacknowledged heartbeat was sent, including heartbeats requested by the server.
+	sentMutex   sync.Mutex
+	sent        time.Time
+	outstanding bool
+
+	// Closed to stop sending heartbeats.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Round trip times between heartbeats and their acks.
+type heartbeatLatency struct {
+	mutex    sync.Mutex
+	last     time.Duration
+	smoothed time.Duration
+}
+
+// Weight of the newest sample in the smoothed latency, as used for TCP's smoothed RTT.
+const latencySmoothingFactor = 8
+
+func (l *heartbeatLatency) record(rtt time.Duration) {
+	l.mutex.Lock()
+	l.last = rtt
+	if l.smoothed == 0 {
+		l.smoothed = rtt
+	} else {
+		l.smoothed += (rtt - l.smoothed) / latencySmoothingFactor
+	}
+	l.mutex.Unlock()
+}
+
+func (l *heartbeatLatency) get() (last time.Duration, smoothed time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.last, l.smoothed
 }
 
 // Called when a heartbeat ACK is received. Forwards the current sequence num to the ACK channel.
 func (d *discordHeartbeat) heartbeatAckRecv(GatewayPayload) {
+	d.acknowledge()
+}
+
+// Records the latency of the outstanding heartbeat and signals the heartbeat loop. Acks without
+// an outstanding heartbeat are ignored, so that a late ack can't stand in for a later heartbeat.
+func (d *discordHeartbeat) acknowledge() {
 	log.Print("Received heartbeat ack")
-	d.heartbeatAck <- true
+
+	d.sentMutex.Lock()
+	outstanding := d.outstanding
+	d.outstanding = false
+	sent := d.sent
+	d.sentMutex.Unlock()
+
+	if !outstanding {
+		log.Print("Ignoring heartbeat ack without an outstanding heartbeat.")
+		return
+	}
+
+	d.latency.record(time.Since(sent))
+
+	select {
+	case d.heartbeatAck <- true:
+	default:
+	}
 }
 
-// Called when a heartbeat is received. Updates the sequence num and responds with an ACK.
+// Called when a heartbeat is received. The server expects a heartbeat to be sent in response.
 func (d *discordHeartbeat) heartbeatRecv(payload GatewayPayload) {
 	log.Print("Received heartbeat with payload:", payload)
-	d.gateway.SendPayload(&GatewayPayload{Opcode: OpcodeHeartbeatACK})
+	d.send()
+}
+
+// Sends a heartbeat, timing it from the oldest unacknowledged heartbeat since acks arrive in order.
+func (d *discordHeartbeat) send() error {
+	d.sentMutex.Lock()
+	if !d.outstanding {
+		d.sent = time.Now()
+		d.outstanding = true
+	}
+	d.sentMutex.Unlock()
+
+	return d.conn.sendHeartbeat()
 }
 
 const closeTimeoutSeconds = time.Duration(5) * time.Second
 
-// TODO: retry functionality.
-func startHeartbeat(heartbeat *discordHeartbeat) {
-	heartbeat.heartbeatAck = make(chan bool)
-	heartbeatMessage := GatewayPayload{
-		Opcode: OpcodeHeartbeat,
+// Acks may be received as soon as the connection is read from, so the heartbeat is
+// created up front and started once the interval is known.
+func newDiscordHeartbeat(conn heartbeatConn, latency *heartbeatLatency) *discordHeartbeat {
+	return &discordHeartbeat{
+		conn:         conn,
+		latency:      latency,
+		heartbeatAck: make(chan bool, 1),
+		stop:         make(chan struct{}),
 	}
+}
+
+func startHeartbeat(heartbeat *discordHeartbeat, interval time.Duration) {
+	heartbeat.interval = interval
 
 	go func() {
 		log.Printf("Starting heartbeat with interval: [%v].", heartbeat.interval)
+
+		// The first heartbeat is sent after a random fraction of the interval so that
+		// clients reconnecting at the same time don't all heartbeat at once.
+		// Reference: https://discordapp.com/developers/docs/topics/gateway#heartbeating
+		wait := time.Duration(rand.Float64() * float64(heartbeat.interval))
+
 		for {
-			heartbeatMessage.SequenceNumber = heartbeat.getSequenceNum()
+			select {
+			case <-time.After(wait):
+			case <-heartbeat.stop:
+				return
+			}
+
+			// Drop an ack for a heartbeat the server requested since the last one.
+			select {
+			case <-heartbeat.heartbeatAck:
+			default:
+			}
 
 			log.Print("Sending heartbeat.")
-			err := heartbeat.gateway.SendPayload(&heartbeatMessage)
+			err := heartbeat.send()
 
 			if err != nil {
-				log.Fatal("Failed to send heartbeat: ", err)
+				log.Print("Failed to send heartbeat: ", err)
+				heartbeat.conn.heartbeatFailed(fmt.Errorf("failed to send heartbeat: %v", err))
+				return
 			}
 
 			lastHeartbeat := time.Now()
 
 			select {
 			case <-heartbeat.heartbeatAck:
-				timeSinceLast := time.Now().Sub(lastHeartbeat)
-				log.Printf("Sleeping after heartbeat ack.")
-				time.Sleep(heartbeat.interval - timeSinceLast)
+				wait = heartbeat.interval - time.Since(lastHeartbeat)
+			case <-heartbeat.stop:
+				return
 			case <-time.After(heartbeat.interval):
-				err := heartbeat.gateway.SendControl(
-					websocket.CloseMessage,
-					websocket.FormatCloseMessage(CloseSessionTimeout, ""),
-					time.Now().Add(closeTimeoutSeconds),
-				)
-
-				if err != nil {
-					log.Printf("gateway failed to close with error: %v", err)
-				}
-
-				log.Fatal("Heartbeat ack not received within time window.")
+				// No ack means the connection is a zombie, even if the socket is still open.
+				log.Print("Heartbeat ack not received within time window.")
+				heartbeat.conn.heartbeatFailed(
+					fmt.Errorf("heartbeat ack not received within [%v]", heartbeat.interval))
+				return
 			}
 		}
 	}()
 }
+
+// Stops sending heartbeats. Safe to call more than once.
+func stopHeartbeat(heartbeat *discordHeartbeat) {
+	heartbeat.stopOnce.Do(func() {
+		close(heartbeat.stop)
+	})
+}
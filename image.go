@@ -0,0 +1,45 @@
+package discordbot
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Image types accepted for emojis, avatars, guild icons etc.
+// Reference: https://discordapp.com/developers/docs/reference#image-data
+var imageDataTypes = map[string]bool{"image/png": true, "image/jpeg": true, "image/gif": true}
+
+// Reads an image and encodes it as a data URI. Fails if the image is larger than maxSize bytes
+// or isn't one of the allowed types, which are detected from the content.
+func imageDataUri(image io.Reader, maxSize int, allowedTypes map[string]bool) (string, error) {
+	data, err := readLimited(image, maxSize)
+
+	if err != nil {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(data)
+	if !allowedTypes[contentType] {
+		return "", fmt.Errorf("unsupported image type [%s]", contentType)
+	}
+
+	return fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// Reads everything from the reader, failing if there is more than maxSize bytes.
+func readLimited(r io.Reader, maxSize int) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("file is larger than the maximum of %d bytes", maxSize)
+	}
+
+	return data, nil
+}
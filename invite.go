@@ -0,0 +1,110 @@
+package discordbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/invite#invite-object-invite-structure
+type Invite struct {
+	Code string `json:"code"`
+	// Partial guild, not set for group DM invites.
+	Guild *Guild `json:"guild,omitempty"`
+	// Partial channel.
+	Channel        Channel `json:"channel"`
+	Inviter        *User   `json:"inviter,omitempty"`
+	TargetUser     *User   `json:"target_user,omitempty"`
+	TargetUserType *int    `json:"target_user_type,omitempty"`
+	// Only set when requested with counts.
+	ApproximatePresenceCount *int `json:"approximate_presence_count,omitempty"`
+	ApproximateMemberCount   *int `json:"approximate_member_count,omitempty"`
+}
+
+// Invite with usage details, returned when listing a channel's or guild's invites.
+// Reference: https://discordapp.com/developers/docs/resources/invite#invite-metadata-object-invite-metadata-structure
+type InviteMetadata struct {
+	Invite
+	Uses int `json:"uses"`
+	// 0 means unlimited.
+	MaxUses int `json:"max_uses"`
+	// Seconds the invite is valid for, 0 means forever.
+	MaxAge int `json:"max_age"`
+	// Members that joined through a temporary invite are kicked when they disconnect, unless given a role.
+	Temporary bool      `json:"temporary"`
+	CreatedAt Timestamp `json:"created_at"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#create-channel-invite-json-params
+type InviteOptions struct {
+	// Seconds until the invite expires, 0 for never. Defaults to 24 hours when nil.
+	MaxAge *int `json:"max_age,omitempty"`
+	// 0 means unlimited.
+	MaxUses   int  `json:"max_uses,omitempty"`
+	Temporary bool `json:"temporary,omitempty"`
+	// Always create a new invite instead of reusing a similar one.
+	Unique bool `json:"unique,omitempty"`
+}
+
+const invitesEndpoint = "/invites"
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#get-channel-invites
+func (client *DiscordClient) GetChannelInvites(channelId Snowflake) (invites []InviteMetadata, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/invites", channelsEnpoint, channelId),
+		result:   &invites,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#create-channel-invite
+func (client *DiscordClient) CreateChannelInvite(
+	channelId Snowflake, options InviteOptions, reason string,
+) (invite Invite, err error) {
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPost,
+		endpoint: fmt.Sprintf("%s/%s/invites", channelsEnpoint, channelId),
+		body:     &options,
+		reason:   reason,
+		result:   &invite,
+	})
+	return
+}
+
+// Gets an invite by its code. With counts, the approximate member and presence counts of the guild are set.
+// Reference: https://discordapp.com/developers/docs/resources/invite#get-invite
+func (client *DiscordClient) GetInvite(code string, withCounts bool) (invite Invite, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: invitesEndpoint + "/" + url.PathEscape(code),
+		query:    url.Values{"with_counts": {strconv.FormatBool(withCounts)}},
+		result:   &invite,
+	})
+	return
+}
+
+// Deletes an invite, returning it.
+// Reference: https://discordapp.com/developers/docs/resources/invite#delete-invite
+func (client *DiscordClient) DeleteInvite(code string, reason string) (invite Invite, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: invitesEndpoint + "/" + url.PathEscape(code),
+		reason:   reason,
+		result:   &invite,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-invites
+func (client *DiscordClient) GetGuildInvites(guildId Snowflake) (invites []InviteMetadata, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/invites", guildsEndpoint, guildId),
+		result:   &invites,
+	})
+	return
+}
@@ -0,0 +1,63 @@
+package discordbot_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestCreateChannelInvite(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"code":    "0vCdhLbwjZZTWZLD",
+			"guild":   map[string]string{"id": "165176875973476352", "name": "CS:GO Fraggers Only"},
+			"channel": map[string]interface{}{"id": "165176875973476352", "name": "illuminati", "type": 0},
+			"inviter": map[string]string{"id": "80351110224678912", "username": "Nelly"},
+		}
+	})
+
+	never := 0
+	client := api.client(t)
+	invite, err := client.CreateChannelInvite(165176875973476352, discordbot.InviteOptions{MaxAge: &never, Unique: true}, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invite.Guild == nil || invite.Guild.Name != "CS:GO Fraggers Only" || invite.Inviter == nil || invite.Channel.Id != 165176875973476352 {
+		t.Errorf("unexpected invite %+v", invite)
+	}
+
+	request := api.single(t)
+	if request.Path != "/channels/165176875973476352/invites" || string(request.Body) != `{"max_age":0,"unique":true}` {
+		t.Errorf("unexpected request %s %s", request.Path, request.Body)
+	}
+}
+
+func TestGetGuildInvites(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, []map[string]interface{}{{
+			"code":       "0vCdhLbwjZZTWZLD",
+			"channel":    map[string]interface{}{"id": "1", "type": 0},
+			"uses":       3,
+			"max_uses":   10,
+			"max_age":    3600,
+			"temporary":  true,
+			"created_at": "2016-03-31T19:15:39.954000+00:00",
+		}}
+	})
+
+	client := api.client(t)
+	invites, err := client.GetGuildInvites(2)
+
+	if err != nil || len(invites) != 1 {
+		t.Fatalf("unexpected invites %+v: %v", invites, err)
+	}
+	if invite := invites[0]; invite.Code != "0vCdhLbwjZZTWZLD" || invite.Uses != 3 || !invite.Temporary || invite.CreatedAt.IsZero() {
+		t.Errorf("unexpected invite %+v", invite)
+	}
+
+	if request := api.single(t); request.Path != "/guilds/2/invites" {
+		t.Errorf("unexpected path %s", request.Path)
+	}
+}
@@ -0,0 +1,259 @@
+package discordbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#message-object-message-flags
+const (
+	MessageFlagCrossposted          = 1 << 0
+	MessageFlagIsCrosspost          = 1 << 1
+	MessageFlagSuppressEmbeds       = 1 << 2
+	MessageFlagSourceMessageDeleted = 1 << 3
+	MessageFlagUrgent               = 1 << 4
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#embed-object-embed-structure
+type Embed struct {
+	Title string `json:"title,omitempty"`
+	// Always "rich" for embeds sent by bots.
+	Type        string         `json:"type,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Url         string         `json:"url,omitempty"`
+	Timestamp   *Timestamp     `json:"timestamp,omitempty"`
+	Color       Color          `json:"color,omitempty"`
+	Footer      *EmbedFooter   `json:"footer,omitempty"`
+	Image       *EmbedMedia    `json:"image,omitempty"`
+	Thumbnail   *EmbedMedia    `json:"thumbnail,omitempty"`
+	Video       *EmbedMedia    `json:"video,omitempty"`
+	Provider    *EmbedProvider `json:"provider,omitempty"`
+	Author      *EmbedAuthor   `json:"author,omitempty"`
+	Fields      []EmbedField   `json:"fields,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#embed-object-embed-footer-structure
+type EmbedFooter struct {
+	Text         string `json:"text"`
+	IconUrl      string `json:"icon_url,omitempty"`
+	ProxyIconUrl string `json:"proxy_icon_url,omitempty"`
+}
+
+// Image, thumbnail or video of an embed.
+// Reference: https://discordapp.com/developers/docs/resources/channel#embed-object-embed-image-structure
+type EmbedMedia struct {
+	Url      string `json:"url,omitempty"`
+	ProxyUrl string `json:"proxy_url,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	Width    int    `json:"width,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#embed-object-embed-provider-structure
+type EmbedProvider struct {
+	Name string `json:"name,omitempty"`
+	Url  string `json:"url,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#embed-object-embed-author-structure
+type EmbedAuthor struct {
+	Name         string `json:"name,omitempty"`
+	Url          string `json:"url,omitempty"`
+	IconUrl      string `json:"icon_url,omitempty"`
+	ProxyIconUrl string `json:"proxy_icon_url,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#embed-object-embed-field-structure
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// Bounds of a GetChannelMessages request. At most one of Around, Before and After may be set;
+// zero means unset.
+type MessagesQuery struct {
+	Around Snowflake
+	Before Snowflake
+	After  Snowflake
+	// 1-100, defaults to 50.
+	Limit int
+}
+
+const maxMessagesPerRequest = 100
+
+func (q MessagesQuery) values() (values url.Values, err error) {
+	values = url.Values{}
+
+	bounds := 0
+	for name, id := range map[string]Snowflake{"around": q.Around, "before": q.Before, "after": q.After} {
+		if id != 0 {
+			values.Set(name, id.String())
+			bounds++
+		}
+	}
+
+	if bounds > 1 {
+		return nil, fmt.Errorf("only one of around, before and after can be set")
+	}
+
+	if q.Limit < 0 || q.Limit > maxMessagesPerRequest {
+		return nil, fmt.Errorf("limit [%d] must be between 1 and %d", q.Limit, maxMessagesPerRequest)
+	}
+
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return
+}
+
+// Gets messages in a channel, newest first.
+// Reference: https://discordapp.com/developers/docs/resources/channel#get-channel-messages
+func (client *DiscordClient) GetChannelMessages(channelId Snowflake, query MessagesQuery) ([]Message, error) {
+	return client.getChannelMessages(context.Background(), channelId, query)
+}
+
+func (client *DiscordClient) getChannelMessages(
+	ctx context.Context, channelId Snowflake, query MessagesQuery,
+) (messages []Message, err error) {
+
+	values, err := query.values()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.do(ctx, restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/messages", channelsEnpoint, channelId),
+		query:    values,
+		result:   &messages,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#get-channel-message
+func (client *DiscordClient) GetChannelMessage(channelId Snowflake, messageId Snowflake) (message Message, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/messages/%s", channelsEnpoint, channelId, messageId),
+		result:   &message,
+	})
+	return
+}
+
+// Changes to a message.
+// Reference: https://discordapp.com/developers/docs/resources/channel#edit-message-json-params
+type MessageEdit struct {
+	fields jsonPatch
+}
+
+// Nil removes the content, which requires the message to have an embed.
+func (e *MessageEdit) SetContent(content *string) *MessageEdit {
+	e.fields.set("content", content)
+	return e
+}
+
+// Nil removes the embed.
+func (e *MessageEdit) SetEmbed(embed *Embed) *MessageEdit {
+	e.fields.set("embed", embed)
+	return e
+}
+
+// Only MessageFlagSuppressEmbeds can be changed.
+func (e *MessageEdit) SetFlags(flags int) *MessageEdit {
+	e.fields.set("flags", flags)
+	return e
+}
+
+func (e MessageEdit) MarshalJSON() ([]byte, error) {
+	return e.fields.marshal()
+}
+
+// Edits a message sent by the current user. Other users' messages can only have their flags changed.
+// Reference: https://discordapp.com/developers/docs/resources/channel#edit-message
+func (client *DiscordClient) EditMessage(
+	channelId Snowflake, messageId Snowflake, edit MessageEdit, reason string,
+) (message Message, err error) {
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s/messages/%s", channelsEnpoint, channelId, messageId),
+		body:     edit,
+		reason:   reason,
+		result:   &message,
+	})
+	return
+}
+
+// Only deleting another user's message is recorded in the audit log.
+// Reference: https://discordapp.com/developers/docs/resources/channel#delete-message
+func (client *DiscordClient) DeleteMessage(channelId Snowflake, messageId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/messages/%s", channelsEnpoint, channelId, messageId),
+		reason:   reason,
+	})
+}
+
+// Bulk delete only accepts messages younger than this. A minute is taken off so that messages
+// don't age past the limit while the request is in flight.
+const bulkDeleteMaxAge = time.Duration(14*24)*time.Hour - time.Minute
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#bulk-delete-messages-json-params
+type bulkDeleteMessages struct {
+	Messages []Snowflake `json:"messages"`
+}
+
+// Deletes any number of messages, in batches of up to 100. Messages older than two weeks can't be
+// bulk deleted, so they are skipped and returned.
+// Reference: https://discordapp.com/developers/docs/resources/channel#bulk-delete-messages
+func (client *DiscordClient) BulkDeleteMessages(
+	channelId Snowflake, messageIds []Snowflake, reason string,
+) (skipped []Snowflake, err error) {
+
+	oldest := SnowflakeFromTime(time.Now().Add(-bulkDeleteMaxAge))
+
+	// Duplicate IDs are rejected.
+	seen := make(map[Snowflake]bool)
+	deletable := []Snowflake{}
+	for _, messageId := range messageIds {
+		switch {
+		case seen[messageId]:
+		case messageId < oldest:
+			skipped = append(skipped, messageId)
+		default:
+			deletable = append(deletable, messageId)
+		}
+		seen[messageId] = true
+	}
+
+	for len(deletable) > 0 {
+		batch := deletable
+		if len(batch) > maxMessagesPerRequest {
+			batch = batch[:maxMessagesPerRequest]
+		}
+		deletable = deletable[len(batch):]
+
+		// Bulk delete needs at least 2 messages.
+		if len(batch) == 1 {
+			err = client.DeleteMessage(channelId, batch[0], reason)
+		} else {
+			err = client.do(context.Background(), restRequest{
+				method:   http.MethodPost,
+				endpoint: fmt.Sprintf("%s/%s/messages/bulk-delete", channelsEnpoint, channelId),
+				body:     bulkDeleteMessages{Messages: batch},
+				reason:   reason,
+			})
+		}
+
+		if err != nil {
+			return skipped, fmt.Errorf("failed to delete messages: %w", err)
+		}
+	}
+
+	return
+}
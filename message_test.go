@@ -0,0 +1,107 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestGetChannelMessages(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, []map[string]interface{}{{"id": "3", "channel_id": "1", "content": "hi"}}
+	})
+
+	client := api.client(t)
+	messages, err := client.GetChannelMessages(1, discordbot.MessagesQuery{Before: 4, Limit: 10})
+
+	if err != nil || len(messages) != 1 || messages[0].Content != "hi" {
+		t.Fatalf("unexpected messages %+v: %v", messages, err)
+	}
+
+	request := api.single(t)
+	if request.Path != "/channels/1/messages" || request.Query != "before=4&limit=10" {
+		t.Errorf("unexpected request %s?%s", request.Path, request.Query)
+	}
+
+	_, err = client.GetChannelMessages(1, discordbot.MessagesQuery{Before: 4, After: 2})
+	if err == nil {
+		t.Error("expected an error with both before and after set")
+	}
+}
+
+func TestEditMessage(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"id": "2", "channel_id": "1"}
+	})
+
+	edit := discordbot.MessageEdit{}
+	edit.SetEmbed(nil).SetFlags(discordbot.MessageFlagSuppressEmbeds)
+
+	client := api.client(t)
+	if _, err := client.EditMessage(1, 2, edit, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	request := api.single(t)
+	if request.Method != http.MethodPatch || string(request.Body) != `{"embed":null,"flags":4}` {
+		t.Errorf("unexpected request %s %s", request.Method, request.Body)
+	}
+}
+
+func TestBulkDeleteMessages(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNoContent, nil
+	})
+
+	recent := discordbot.SnowflakeFromTime(time.Now().Add(-time.Hour))
+	old := discordbot.SnowflakeFromTime(time.Now().Add(-15 * 24 * time.Hour))
+
+	messageIds := []discordbot.Snowflake{old, recent}
+	for i := 0; i < 200; i++ {
+		messageIds = append(messageIds, recent+discordbot.Snowflake(i))
+	}
+
+	client := api.client(t)
+	skipped, err := client.BulkDeleteMessages(1, messageIds, "spam")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 1 || skipped[0] != old {
+		t.Errorf("expected the old message to be skipped, got %v", skipped)
+	}
+
+	// 200 unique recent messages are deleted in two full batches.
+	requests := api.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	for _, request := range requests {
+		body := struct {
+			Messages []discordbot.Snowflake `json:"messages"`
+		}{}
+		json.Unmarshal(request.Body, &body)
+
+		if !strings.HasSuffix(request.Path, "/messages/bulk-delete") || len(body.Messages) != 100 {
+			t.Errorf("unexpected batch to %s of %d messages", request.Path, len(body.Messages))
+		}
+		if request.Header.Get("X-Audit-Log-Reason") != "spam" {
+			t.Error("missing audit log reason")
+		}
+	}
+
+	// A single message can't be bulk deleted.
+	if _, err := client.BulkDeleteMessages(1, []discordbot.Snowflake{recent}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	requests = api.received()
+	if last := requests[len(requests)-1]; last.Method != http.MethodDelete || last.Path != "/channels/1/messages/"+recent.String() {
+		t.Errorf("unexpected request %s %s", last.Method, last.Path)
+	}
+}
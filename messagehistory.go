@@ -0,0 +1,156 @@
+package discordbot
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Where a MessageHistory starts and when it stops.
+type MessageHistoryOptions struct {
+	// Walk from older to newer messages instead of newest first.
+	Forwards bool
+	// Start after this message, which is excluded. Zero starts at the newest message, or the oldest
+	// when walking forwards.
+	Start Snowflake
+	// Stop at messages created before this time, or after it when walking forwards. Zero means no bound.
+	Until time.Time
+	// Stop after this many messages. 0 means no limit.
+	Limit int
+}
+
+// Iterates over a channel's messages, fetching a page at a time with GetChannelMessages.
+//
+//	history := client.MessageHistory(ctx, channelId, discordbot.MessageHistoryOptions{})
+//	for history.Next() {
+//		archive(history.Message())
+//	}
+//	if err := history.Err(); err != nil {
+//		log.Print("Failed to read history: ", err)
+//	}
+type MessageHistory struct {
+	client    *DiscordClient
+	ctx       context.Context
+	channelId Snowflake
+	options   MessageHistoryOptions
+
+	// Messages fetched but not yet returned, in iteration order.
+	page    []Message
+	current Message
+	// ID the next page is fetched relative to.
+	cursor Snowflake
+	count  int
+	done   bool
+	err    error
+}
+
+// Creates an iterator over a channel's messages. No requests are made until Next is called.
+// Requests wait out rate limits and stop when the context is done.
+func (client *DiscordClient) MessageHistory(
+	ctx context.Context, channelId Snowflake, options MessageHistoryOptions,
+) *MessageHistory {
+
+	cursor := options.Start
+	// Messages after ID 1 are all messages, oldest first.
+	if options.Forwards && cursor == 0 {
+		cursor = 1
+	}
+
+	return &MessageHistory{
+		client:    client,
+		ctx:       ctx,
+		channelId: channelId,
+		options:   options,
+		cursor:    cursor,
+	}
+}
+
+// Advances to the next message, fetching another page if needed. Returns false once the history
+// or a bound is reached, or on error.
+func (h *MessageHistory) Next() bool {
+	if h.options.Limit > 0 && h.count >= h.options.Limit {
+		h.done = true
+	}
+
+	if len(h.page) == 0 && !h.done {
+		h.fetch()
+	}
+
+	if len(h.page) == 0 {
+		return false
+	}
+
+	message := h.page[0]
+	h.page = h.page[1:]
+
+	if h.pastUntil(message) {
+		h.done = true
+		h.page = nil
+		return false
+	}
+
+	h.current = message
+	h.count++
+	return true
+}
+
+// Message the iterator is at.
+func (h *MessageHistory) Message() Message {
+	return h.current
+}
+
+// Error that stopped the iteration, if any.
+func (h *MessageHistory) Err() error {
+	return h.err
+}
+
+func (h *MessageHistory) fetch() {
+	query := MessagesQuery{Limit: maxMessagesPerRequest}
+	if remaining := h.options.Limit - h.count; h.options.Limit > 0 && remaining < query.Limit {
+		query.Limit = remaining
+	}
+
+	if h.options.Forwards {
+		query.After = h.cursor
+	} else {
+		query.Before = h.cursor
+	}
+
+	messages, err := h.client.getChannelMessages(h.ctx, h.channelId, query)
+
+	if err != nil {
+		h.err = err
+		h.done = true
+		return
+	}
+
+	if len(messages) < query.Limit {
+		h.done = true
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	// Pages are sorted newest first.
+	sort.Slice(messages, func(i, j int) bool {
+		if h.options.Forwards {
+			return messages[i].Id < messages[j].Id
+		}
+		return messages[i].Id > messages[j].Id
+	})
+
+	h.page = messages
+	h.cursor = messages[len(messages)-1].Id
+}
+
+func (h *MessageHistory) pastUntil(message Message) bool {
+	if h.options.Until.IsZero() {
+		return false
+	}
+
+	if h.options.Forwards {
+		return message.Id.Time().After(h.options.Until)
+	}
+	return message.Id.Time().Before(h.options.Until)
+}
@@ -0,0 +1,132 @@
+package discordbot_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+// Serves a channel of 250 messages created a second apart, paginated like Discord, newest first.
+func newFakeHistoryApi(t *testing.T, start time.Time) (*fakeApi, []discordbot.Snowflake) {
+	ids := []discordbot.Snowflake{}
+	for i := 0; i < 250; i++ {
+		ids = append(ids, discordbot.SnowflakeFromTime(start.Add(time.Duration(i)*time.Second)))
+	}
+
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		query, _ := url.ParseQuery(request.Query)
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		before, _ := discordbot.ParseSnowflake(query.Get("before"))
+		after, _ := discordbot.ParseSnowflake(query.Get("after"))
+
+		page := []map[string]string{}
+		if query.Get("after") != "" {
+			// The messages immediately after, still newest first.
+			matched := []discordbot.Snowflake{}
+			for _, id := range ids {
+				if id > after && len(matched) < limit {
+					matched = append(matched, id)
+				}
+			}
+			for i := len(matched) - 1; i >= 0; i-- {
+				page = append(page, map[string]string{"id": matched[i].String(), "channel_id": "1"})
+			}
+		} else {
+			for i := len(ids) - 1; i >= 0 && len(page) < limit; i-- {
+				if before == 0 || ids[i] < before {
+					page = append(page, map[string]string{"id": ids[i].String(), "channel_id": "1"})
+				}
+			}
+		}
+		return http.StatusOK, page
+	})
+	return api, ids
+}
+
+func TestMessageHistoryBackwards(t *testing.T) {
+	api, ids := newFakeHistoryApi(t, time.Now().Add(-time.Hour))
+
+	client := api.client(t)
+	history := client.MessageHistory(context.Background(), 1, discordbot.MessageHistoryOptions{})
+
+	count := 0
+	for history.Next() {
+		if expected := ids[len(ids)-1-count]; history.Message().Id != expected {
+			t.Fatalf("message %d is %v, expected %v", count, history.Message().Id, expected)
+		}
+		count++
+	}
+
+	if err := history.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != len(ids) {
+		t.Errorf("expected %d messages, got %d", len(ids), count)
+	}
+	if requests := len(api.received()); requests != 3 {
+		t.Errorf("expected 3 pages, got %d", requests)
+	}
+}
+
+func TestMessageHistoryForwardsWithBounds(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	api, ids := newFakeHistoryApi(t, start)
+
+	client := api.client(t)
+	history := client.MessageHistory(context.Background(), 1, discordbot.MessageHistoryOptions{
+		Forwards: true,
+		Start:    ids[9],
+		Limit:    150,
+	})
+
+	count := 0
+	for history.Next() {
+		if expected := ids[10+count]; history.Message().Id != expected {
+			t.Fatalf("message %d is %v, expected %v", count, history.Message().Id, expected)
+		}
+		count++
+	}
+
+	if err := history.Err(); err != nil || count != 150 {
+		t.Fatalf("expected 150 messages, got %d: %v", count, err)
+	}
+
+	// The second page only asks for the remaining messages.
+	requests := api.received()
+	if len(requests) != 2 || requests[1].Query != "after="+ids[109].String()+"&limit=50" {
+		t.Errorf("unexpected requests %+v", requests)
+	}
+
+	history = client.MessageHistory(context.Background(), 1, discordbot.MessageHistoryOptions{
+		Forwards: true,
+		Until:    start.Add(20*time.Second + time.Millisecond),
+	})
+
+	count = 0
+	for history.Next() {
+		count++
+	}
+
+	if count != 21 {
+		t.Errorf("expected the 21 messages up to the time bound, got %d", count)
+	}
+}
+
+func TestMessageHistoryStopsOnCancel(t *testing.T) {
+	api, _ := newFakeHistoryApi(t, time.Now().Add(-time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := api.client(t)
+	history := client.MessageHistory(ctx, 1, discordbot.MessageHistoryOptions{})
+
+	if history.Next() || history.Err() == nil {
+		t.Error("expected the cancelled context to stop the iteration with an error")
+	}
+}
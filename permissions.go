@@ -0,0 +1,117 @@
+package discordbot
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Bitfield of permissions granted by a role or overwrite. Sent as a number, but strings are
+// also accepted when decoding.
+// Reference: https://discordapp.com/developers/docs/topics/permissions#permissions-bitwise-permission-flags
+type Permissions uint64
+
+const (
+	PermissionCreateInstantInvite Permissions = 1 << 0
+	PermissionKickMembers         Permissions = 1 << 1
+	PermissionBanMembers          Permissions = 1 << 2
+	// Grants every permission and bypasses channel overwrites.
+	PermissionAdministrator      Permissions = 1 << 3
+	PermissionManageChannels     Permissions = 1 << 4
+	PermissionManageGuild        Permissions = 1 << 5
+	PermissionAddReactions       Permissions = 1 << 6
+	PermissionViewAuditLog       Permissions = 1 << 7
+	PermissionPrioritySpeaker    Permissions = 1 << 8
+	PermissionStream             Permissions = 1 << 9
+	PermissionViewChannel        Permissions = 1 << 10
+	PermissionSendMessages       Permissions = 1 << 11
+	PermissionSendTtsMessages    Permissions = 1 << 12
+	PermissionManageMessages     Permissions = 1 << 13
+	PermissionEmbedLinks         Permissions = 1 << 14
+	PermissionAttachFiles        Permissions = 1 << 15
+	PermissionReadMessageHistory Permissions = 1 << 16
+	PermissionMentionEveryone    Permissions = 1 << 17
+	PermissionUseExternalEmojis  Permissions = 1 << 18
+	PermissionViewGuildInsights  Permissions = 1 << 19
+	PermissionConnect            Permissions = 1 << 20
+	PermissionSpeak              Permissions = 1 << 21
+	PermissionMuteMembers        Permissions = 1 << 22
+	PermissionDeafenMembers      Permissions = 1 << 23
+	PermissionMoveMembers        Permissions = 1 << 24
+	PermissionUseVad             Permissions = 1 << 25
+	PermissionChangeNickname     Permissions = 1 << 26
+	PermissionManageNicknames    Permissions = 1 << 27
+	PermissionManageRoles        Permissions = 1 << 28
+	PermissionManageWebhooks     Permissions = 1 << 29
+	PermissionManageEmojis       Permissions = 1 << 30
+	// Needed to time out members.
+	PermissionModerateMembers Permissions = 1 << 40
+)
+
+// Whether all of the given permissions are set. Administrator is not treated specially.
+func (p Permissions) Has(permissions Permissions) bool {
+	return p&permissions == permissions
+}
+
+// Whether all of the given permissions are set, or Administrator is.
+func (p Permissions) Allows(permissions Permissions) bool {
+	return p.Has(PermissionAdministrator) || p.Has(permissions)
+}
+
+// Returns the permissions with the given ones set.
+func (p Permissions) Add(permissions Permissions) Permissions {
+	return p | permissions
+}
+
+// Returns the permissions with the given ones cleared.
+func (p Permissions) Remove(permissions Permissions) Permissions {
+	return p &^ permissions
+}
+
+// Accepts numbers as well as strings. Null leaves the permissions unchanged.
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	text := string(bytes.Trim(data, `"`))
+	value, err := strconv.ParseUint(text, 10, 64)
+
+	if err != nil {
+		return fmt.Errorf("invalid permissions [%s]: %v", text, err)
+	}
+
+	*p = Permissions(value)
+	return nil
+}
+
+// RGB color as used by roles and embeds. 0 means no color for roles.
+type Color int
+
+func NewColor(red uint8, green uint8, blue uint8) Color {
+	return Color(int(red)<<16 | int(green)<<8 | int(blue))
+}
+
+// Parses a hex color such as #5865f2 or 5865F2.
+func ParseColor(hex string) (Color, error) {
+	if len(hex) > 0 && hex[0] == '#' {
+		hex = hex[1:]
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 24)
+
+	if err != nil || len(hex) != 6 {
+		return 0, fmt.Errorf("invalid hex color [%s]", hex)
+	}
+
+	return Color(value), nil
+}
+
+func (c Color) RGB() (red uint8, green uint8, blue uint8) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c)
+}
+
+// Lower case hex form, e.g. #5865f2.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%06x", int(c)&0xFFFFFF)
+}
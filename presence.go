@@ -0,0 +1,88 @@
+package discordbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#activity-object-activity-structure
+type Activity struct {
+	Name string `json:"name"`
+	Type int    `json:"type"`
+	// Stream URL, only used with ActivityTypeStreaming.
+	Url *string `json:"url,omitempty"`
+	// Custom status text, only used with ActivityTypeCustom.
+	State *string `json:"state,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#activity-object-activity-types
+const (
+	// Playing {name}
+	ActivityTypeGame = 0
+	// Streaming {name}
+	ActivityTypeStreaming = 1
+	// Listening to {name}
+	ActivityTypeListening = 2
+	// Watching {name}
+	ActivityTypeWatching = 3
+	// {emoji} {state}
+	ActivityTypeCustom = 4
+)
+
+// Discord allows 5 status updates per minute.
+// Reference: https://discordapp.com/developers/docs/topics/gateway#rate-limiting
+const statusUpdateLimit = 5
+const statusUpdateWindow = time.Duration(60) * time.Second
+
+// Updates the client's presence. Returns an error without sending if the status update rate limit is reached.
+func (g *DiscordGateway) UpdateStatus(status GatewayStatusUpdate) (err error) {
+	if err = g.checkConnected("update status"); err != nil {
+		return
+	}
+
+	var statusJsonBytes json.RawMessage
+	statusJsonBytes, err = json.Marshal(&status)
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal status update: %v", err)
+	}
+
+	now := time.Now()
+	if wait, ok := g.statusLimiter.reserve(now); !ok {
+		return fmt.Errorf("status update rate limit reached, retry in %v", wait)
+	}
+
+	err = g.SendPayload(&GatewayPayload{
+		Opcode:    OpcodeStatusUpdate,
+		EventData: statusJsonBytes,
+	})
+
+	if err != nil {
+		// Nothing was sent, so the slot can be used again.
+		g.statusLimiter.release(now)
+		return fmt.Errorf("failed to send status update: %v", err)
+	}
+
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#presence-update-presence-update-event-fields
+type Presence struct {
+	// Only the ID is guaranteed to be set.
+	User         User          `json:"user"`
+	Roles        []Snowflake   `json:"roles,omitempty"`
+	Game         *Activity     `json:"game"`
+	GuildId      *Snowflake    `json:"guild_id,omitempty"`
+	Status       string        `json:"status"`
+	Activities   []Activity    `json:"activities,omitempty"`
+	ClientStatus *ClientStatus `json:"client_status,omitempty"`
+}
+
+// Status on each platform the user is active on. Unset platforms are offline.
+// Reference: https://discordapp.com/developers/docs/topics/gateway#client-status-object
+type ClientStatus struct {
+	Desktop *string `json:"desktop,omitempty"`
+	Mobile  *string `json:"mobile,omitempty"`
+	Web     *string `json:"web,omitempty"`
+}
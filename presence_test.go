@@ -0,0 +1,56 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestUpdateStatus(t *testing.T) {
+	fake := newFakeGateway(t)
+	gateway := fake.connect(t)
+
+	status := discordbot.GatewayStatusUpdate{
+		Game:   &discordbot.Activity{Name: "with fire", Type: discordbot.ActivityTypeGame},
+		Status: discordbot.StatusIdle,
+	}
+
+	if err := gateway.UpdateStatus(status); err != nil {
+		t.Fatal(err)
+	}
+
+	payload := fake.expect(t, discordbot.OpcodeStatusUpdate)
+	sent := discordbot.GatewayStatusUpdate{}
+	if err := json.Unmarshal(payload.EventData, &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent.Status != discordbot.StatusIdle || sent.Game == nil || sent.Game.Name != "with fire" {
+		t.Errorf("unexpected status update sent: %+v", sent)
+	}
+}
+
+func TestUpdateStatusRateLimit(t *testing.T) {
+	fake := newFakeGateway(t)
+	gateway := fake.connect(t)
+
+	status := discordbot.GatewayStatusUpdate{Status: discordbot.StatusOnline}
+	for i := 0; i < 5; i++ {
+		if err := gateway.UpdateStatus(status); err != nil {
+			t.Fatalf("update [%d] failed: %v", i, err)
+		}
+	}
+
+	if err := gateway.UpdateStatus(status); err == nil {
+		t.Error("expected sixth status update within a minute to be rate limited")
+	}
+}
+
+func TestUpdateStatusBeforeConnect(t *testing.T) {
+	gateway := &discordbot.DiscordGateway{}
+
+	if err := gateway.UpdateStatus(discordbot.GatewayStatusUpdate{Status: discordbot.StatusOnline}); err == nil {
+		t.Error("expected an error when not connected")
+	}
+}
@@ -0,0 +1,54 @@
+package discordbot
+
+import (
+	"sync"
+	"time"
+)
+
+// Sliding window limiter used to stay within Discord's client-side rate limits.
+type rateLimiter struct {
+	mutex  sync.Mutex
+	limit  int
+	window time.Duration
+	// Times of the sends within the current window, oldest first.
+	sent []time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Reserves a send slot at the given time. If no slot is free, returns false and how long until one frees up.
+func (r *rateLimiter) reserve(now time.Time) (wait time.Duration, ok bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	expired := 0
+	for expired < len(r.sent) && now.Sub(r.sent[expired]) >= r.window {
+		expired++
+	}
+	r.sent = r.sent[expired:]
+
+	if len(r.sent) >= r.limit {
+		return r.window - now.Sub(r.sent[0]), false
+	}
+
+	r.sent = append(r.sent, now)
+	return 0, true
+}
+
+// Frees the slot reserved at the given time, for a send that failed.
+func (r *rateLimiter) release(reservedAt time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := len(r.sent) - 1; i >= 0; i-- {
+		if r.sent[i].Equal(reservedAt) {
+			r.sent = append(r.sent[:i], r.sent[i+1:]...)
+			return
+		}
+	}
+}
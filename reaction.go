@@ -0,0 +1,111 @@
+package discordbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#reaction-object-reaction-structure
+type Reaction struct {
+	Count int  `json:"count"`
+	Me    bool `json:"me"`
+	// Only the ID, name and animated fields are sent.
+	Emoji Emoji `json:"emoji"`
+}
+
+// Form of the emoji used by the reaction endpoints: the name of a unicode emoji, or name:id for
+// a custom emoji.
+func (e Emoji) ReactionName() string {
+	if e.Id == nil {
+		return e.Name
+	}
+	return e.Name + ":" + e.Id.String()
+}
+
+// Path segment for a reaction emoji. Accepts a unicode emoji, name:id, or a custom emoji as it
+// appears in message content, e.g. <:name:id> or <a:name:id>.
+func reactionEmojiPath(emoji string) string {
+	emoji = strings.TrimSuffix(strings.TrimPrefix(emoji, "<"), ">")
+	emoji = strings.TrimPrefix(strings.TrimPrefix(emoji, "a:"), ":")
+	return url.PathEscape(emoji)
+}
+
+func reactionsEndpoint(channelId Snowflake, messageId Snowflake, emoji string) string {
+	return fmt.Sprintf("%s/%s/messages/%s/reactions/%s", channelsEnpoint, channelId, messageId, reactionEmojiPath(emoji))
+}
+
+// Reacts to a message as the current user. The emoji is a unicode emoji or a custom emoji as
+// name:id, see Emoji.ReactionName.
+// Reference: https://discordapp.com/developers/docs/resources/channel#create-reaction
+func (client *DiscordClient) CreateReaction(channelId Snowflake, messageId Snowflake, emoji string, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodPut,
+		endpoint: reactionsEndpoint(channelId, messageId, emoji) + "/@me",
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#delete-own-reaction
+func (client *DiscordClient) DeleteOwnReaction(channelId Snowflake, messageId Snowflake, emoji string, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: reactionsEndpoint(channelId, messageId, emoji) + "/@me",
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#delete-user-reaction
+func (client *DiscordClient) DeleteUserReaction(
+	channelId Snowflake, messageId Snowflake, emoji string, userId Snowflake, reason string,
+) error {
+
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: reactionsEndpoint(channelId, messageId, emoji) + "/" + userId.String(),
+		reason:   reason,
+	})
+}
+
+// Page of users that reacted with an emoji. Zero fields are unset.
+type ReactionsQuery struct {
+	// Only users with a greater ID. Set to the last user of the previous page to get the next one.
+	After Snowflake
+	// 1-100, defaults to 25.
+	Limit int
+}
+
+// Gets a page of the users that reacted to a message with the emoji, in ID order.
+// Reference: https://discordapp.com/developers/docs/resources/channel#get-reactions
+func (client *DiscordClient) GetReactions(
+	channelId Snowflake, messageId Snowflake, emoji string, query ReactionsQuery,
+) (users []User, err error) {
+
+	values := url.Values{}
+	if query.After != 0 {
+		values.Set("after", query.After.String())
+	}
+	if query.Limit != 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: reactionsEndpoint(channelId, messageId, emoji),
+		query:    values,
+		result:   &users,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/channel#delete-all-reactions
+func (client *DiscordClient) DeleteAllReactions(channelId Snowflake, messageId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/messages/%s/reactions", channelsEnpoint, channelId, messageId),
+		reason:   reason,
+	})
+}
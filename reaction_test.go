@@ -0,0 +1,73 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestReactionEmojiEncoding(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusNoContent, nil
+	})
+
+	emojiId := discordbot.Snowflake(41771983429993937)
+	custom := discordbot.Emoji{Id: &emojiId, Name: "LUL"}
+
+	client := api.client(t)
+	for _, emoji := range []string{"👍", custom.ReactionName(), "<a:LUL:41771983429993937>"} {
+		if err := client.CreateReaction(1, 2, emoji, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"/channels/1/messages/2/reactions/%F0%9F%91%8D/@me",
+		"/channels/1/messages/2/reactions/LUL:41771983429993937/@me",
+		"/channels/1/messages/2/reactions/LUL:41771983429993937/@me",
+	}
+
+	requests := api.received()
+	for i, request := range requests {
+		if request.Method != http.MethodPut || request.Path != expected[i] {
+			t.Errorf("unexpected request %s %s, expected %s", request.Method, request.Path, expected[i])
+		}
+	}
+}
+
+func TestGetReactions(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, []map[string]string{{"id": "6", "username": "reactor"}}
+	})
+
+	client := api.client(t)
+	users, err := client.GetReactions(1, 2, "🔥", discordbot.ReactionsQuery{After: 5, Limit: 100})
+
+	if err != nil || len(users) != 1 || users[0].Id != 6 {
+		t.Fatalf("unexpected users %+v: %v", users, err)
+	}
+
+	request := api.single(t)
+	if request.Path != "/channels/1/messages/2/reactions/%F0%9F%94%A5" || request.Query != "after=5&limit=100" {
+		t.Errorf("unexpected request %s?%s", request.Path, request.Query)
+	}
+}
+
+func TestMessageReactionAddEvent(t *testing.T) {
+	payload := `{
+		"user_id": "3", "channel_id": "1", "message_id": "2", "guild_id": "4",
+		"member": {"user": {"id": "3"}, "roles": [], "joined_at": "2015-04-26T06:26:56.936000+00:00"},
+		"emoji": {"id": null, "name": "🔥"}
+	}`
+
+	reaction := discordbot.MessageReactionAdd{}
+	if err := json.Unmarshal([]byte(payload), &reaction); err != nil {
+		t.Fatal(err)
+	}
+
+	if reaction.MessageId != 2 || reaction.Member == nil || reaction.Emoji.ReactionName() != "🔥" {
+		t.Errorf("unexpected reaction %+v", reaction)
+	}
+}
@@ -0,0 +1,266 @@
+package discordbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error returned when Discord responds to a REST request with an error status.
+// Reference: https://discordapp.com/developers/docs/topics/opcodes-and-status-codes#json
+type RestError struct {
+	Method     string `json:"-"`
+	Url        string `json:"-"`
+	StatusCode int    `json:"-"`
+	// Discord's JSON error code, 0 if the response had none.
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RestError) Error() string {
+	return fmt.Sprintf("%s %s failed with status [%d], code [%d]: %s", e.Method, e.Url, e.StatusCode, e.Code, e.Message)
+}
+
+// Reason shown in the guild's audit log for a mutating request.
+// Reference: https://discordapp.com/developers/docs/resources/audit-log
+const auditLogReasonHeader = "X-Audit-Log-Reason"
+
+// Number of times a request is retried after being rate limited.
+const restRateLimitRetries = 3
+
+type restRequest struct {
+	method string
+	// Path after the API version, e.g. /channels/{id}.
+	endpoint string
+	query    url.Values
+	// Marshaled as the JSON body if not nil.
+	body interface{}
+	// Sent as the body instead of a JSON body when contentType is set, e.g. for file uploads.
+	data        []byte
+	contentType string
+	// Shown in the audit log if not empty.
+	reason string
+	// Decoded from the JSON response if not nil.
+	result interface{}
+}
+
+// Sends a REST request, waiting out rate limits, and decodes the response into request.result.
+func (client *DiscordClient) do(ctx context.Context, request restRequest) (err error) {
+	requestUrl := client.apiUrl() + request.endpoint
+	if len(request.query) > 0 {
+		requestUrl += "?" + request.query.Encode()
+	}
+
+	bodyBytes := request.data
+	if request.body != nil {
+		bodyBytes, err = json.Marshal(request.body)
+
+		if err != nil {
+			return fmt.Errorf("failed to marshal request to %s: %v", request.endpoint, err)
+		}
+	}
+
+	bucket := client.AuthToken + " " + request.method + " " + restRoute(request.endpoint)
+
+	for attempt := 0; ; attempt++ {
+		if err = restLimits.wait(ctx, client.AuthToken, bucket); err != nil {
+			return
+		}
+
+		var req *http.Request
+		req, err = http.NewRequest(request.method, requestUrl, bytes.NewReader(bodyBytes))
+
+		if err != nil {
+			return
+		}
+
+		req = req.WithContext(ctx)
+		req.Header.Add("Authorization", fmt.Sprintf("%s %s", authTokenType, client.AuthToken))
+		req.Header.Add("User-Agent", userAgent)
+
+		if request.contentType != "" {
+			req.Header.Add("Content-Type", request.contentType)
+		} else if request.body != nil {
+			req.Header.Add("Content-Type", "application/json")
+		}
+
+		if request.reason != "" {
+			req.Header.Add(auditLogReasonHeader, url.PathEscape(request.reason))
+		}
+
+		var resp *http.Response
+		resp, err = client.httpClient().Do(req)
+
+		if err != nil {
+			return fmt.Errorf("%s %s failed: %v", request.method, requestUrl, err)
+		}
+
+		var respBytes []byte
+		respBytes, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil {
+			return fmt.Errorf("failed to read response from %s %s: %v", request.method, requestUrl, err)
+		}
+
+		restLimits.update(bucket, resp.Header)
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < restRateLimitRetries {
+			restLimits.limited(client.AuthToken, bucket, respBytes)
+			log.Printf("Rate limited on %s %s, retrying.", request.method, request.endpoint)
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			restErr := &RestError{Method: request.method, Url: requestUrl, StatusCode: resp.StatusCode}
+			if json.Unmarshal(respBytes, restErr) != nil {
+				restErr.Message = string(respBytes)
+			}
+			return restErr
+		}
+
+		if request.result != nil && len(respBytes) > 0 {
+			err = json.Unmarshal(respBytes, request.result)
+
+			if err != nil {
+				return fmt.Errorf("failed to parse response from %s %s: %v", request.method, requestUrl, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+func (client *DiscordClient) apiUrl() string {
+	base := baseUrl
+	if client.BaseUrl != "" {
+		base = strings.TrimSuffix(client.BaseUrl, "/")
+	}
+	return base + "/v" + strconv.Itoa(apiVersion)
+}
+
+func (client *DiscordClient) httpClient() *http.Client {
+	if client.HttpClient != nil {
+		return client.HttpClient
+	}
+	return http.DefaultClient
+}
+
+// Discord rate limits per route, where the IDs of the top-level channel, guild or webhook are
+// part of the route and all other IDs are not.
+// Reference: https://discordapp.com/developers/docs/topics/rate-limits
+func restRoute(endpoint string) string {
+	segments := strings.Split(endpoint, "/")
+	for i := 2; i < len(segments); i++ {
+		if _, err := strconv.ParseUint(segments[i], 10, 64); err != nil {
+			continue
+		}
+
+		major := i == 2 && (segments[1] == "channels" || segments[1] == "guilds" || segments[1] == "webhooks")
+		if !major {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Rate limits are per token, so they are shared by every client using the same token.
+var restLimits = &restRateLimits{resets: make(map[string]time.Time)}
+
+// Times at which exhausted rate limit buckets reset, keyed by bucket, or by token for the global limit.
+type restRateLimits struct {
+	mutex  sync.Mutex
+	resets map[string]time.Time
+}
+
+// Waits until neither the global limit for the token nor the bucket is exhausted.
+func (r *restRateLimits) wait(ctx context.Context, token string, bucket string) error {
+	for {
+		r.mutex.Lock()
+		reset := r.resets[token]
+		if bucketReset := r.resets[bucket]; bucketReset.After(reset) {
+			reset = bucketReset
+		}
+		r.mutex.Unlock()
+
+		wait := time.Until(reset)
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Records when the bucket resets if the response used up its last request.
+func (r *restRateLimits) update(bucket string, header http.Header) {
+	if header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+
+	resetAfter, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil {
+		return
+	}
+
+	r.mutex.Lock()
+	r.resets[bucket] = time.Now().Add(time.Duration(resetAfter * float64(time.Second)))
+	r.mutex.Unlock()
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/rate-limits#exceeding-a-rate-limit-rate-limit-response-structure
+type restRateLimitResponse struct {
+	// Milliseconds to wait before retrying.
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+// Records the retry delay from a 429 response.
+func (r *restRateLimits) limited(token string, bucket string, body []byte) {
+	limit := restRateLimitResponse{}
+	if err := json.Unmarshal(body, &limit); err != nil {
+		limit.RetryAfter = 1000
+	}
+
+	key := bucket
+	if limit.Global {
+		key = token
+	}
+
+	r.mutex.Lock()
+	r.resets[key] = time.Now().Add(time.Duration(limit.RetryAfter * float64(time.Millisecond)))
+	r.mutex.Unlock()
+}
+
+// Fields of a modify request. Only fields that were set are sent, and nullable fields can be set to null.
+type jsonPatch map[string]interface{}
+
+func (p *jsonPatch) set(field string, value interface{}) {
+	if *p == nil {
+		*p = make(jsonPatch)
+	}
+	(*p)[field] = value
+}
+
+// An empty patch is sent as an empty object rather than null.
+func (p jsonPatch) marshal() ([]byte, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}(p))
+}
@@ -0,0 +1,114 @@
+package discordbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#get-guild-roles
+func (client *DiscordClient) GetGuildRoles(guildId Snowflake) (roles []Role, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/roles", guildsEndpoint, guildId),
+		result:   &roles,
+	})
+	return
+}
+
+// Settings of a role. Fields that aren't set take Discord's defaults when creating a role.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-role-json-params
+type RolePatch struct {
+	fields jsonPatch
+}
+
+func (p *RolePatch) SetName(name string) *RolePatch {
+	p.fields.set("name", name)
+	return p
+}
+
+func (p *RolePatch) SetPermissions(permissions Permissions) *RolePatch {
+	p.fields.set("permissions", permissions)
+	return p
+}
+
+// 0 means no color.
+func (p *RolePatch) SetColor(color Color) *RolePatch {
+	p.fields.set("color", color)
+	return p
+}
+
+// Show members with the role separately in the member list.
+func (p *RolePatch) SetHoist(hoist bool) *RolePatch {
+	p.fields.set("hoist", hoist)
+	return p
+}
+
+func (p *RolePatch) SetMentionable(mentionable bool) *RolePatch {
+	p.fields.set("mentionable", mentionable)
+	return p
+}
+
+func (p RolePatch) MarshalJSON() ([]byte, error) {
+	return p.fields.marshal()
+}
+
+// Creates a role, by default named "new role" with the @everyone permissions.
+// Reference: https://discordapp.com/developers/docs/resources/guild#create-guild-role
+func (client *DiscordClient) CreateGuildRole(guildId Snowflake, settings RolePatch, reason string) (role Role, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPost,
+		endpoint: fmt.Sprintf("%s/%s/roles", guildsEndpoint, guildId),
+		body:     settings,
+		reason:   reason,
+		result:   &role,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-role-positions-json-params
+type RolePosition struct {
+	Id       Snowflake `json:"id"`
+	Position int       `json:"position"`
+}
+
+// Moves roles, returning all of the guild's roles. Only the roles being moved need to be given.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-role-positions
+func (client *DiscordClient) ModifyGuildRolePositions(
+	guildId Snowflake, positions []RolePosition, reason string,
+) (roles []Role, err error) {
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s/roles", guildsEndpoint, guildId),
+		body:     positions,
+		reason:   reason,
+		result:   &roles,
+	})
+	return
+}
+
+// Updates a role.
+// Reference: https://discordapp.com/developers/docs/resources/guild#modify-guild-role
+func (client *DiscordClient) ModifyGuildRole(
+	guildId Snowflake, roleId Snowflake, patch RolePatch, reason string,
+) (role Role, err error) {
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s/roles/%s", guildsEndpoint, guildId, roleId),
+		body:     patch,
+		reason:   reason,
+		result:   &role,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/guild#delete-guild-role
+func (client *DiscordClient) DeleteGuildRole(guildId Snowflake, roleId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/roles/%s", guildsEndpoint, guildId, roleId),
+		reason:   reason,
+	})
+}
@@ -0,0 +1,64 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestPermissions(t *testing.T) {
+	permissions := discordbot.PermissionSendMessages.Add(discordbot.PermissionViewChannel)
+
+	if !permissions.Has(discordbot.PermissionSendMessages|discordbot.PermissionViewChannel) ||
+		permissions.Has(discordbot.PermissionBanMembers) {
+		t.Errorf("unexpected permissions %b", permissions)
+	}
+
+	if permissions.Remove(discordbot.PermissionViewChannel).Has(discordbot.PermissionViewChannel) {
+		t.Error("permission was not removed")
+	}
+
+	if !discordbot.PermissionAdministrator.Allows(discordbot.PermissionBanMembers) {
+		t.Error("administrator should allow everything")
+	}
+
+	// Newer API versions send permissions as strings.
+	role := discordbot.Role{}
+	if err := json.Unmarshal([]byte(`{"id": "1", "permissions": "1099511627776", "color": 3447003}`), &role); err != nil {
+		t.Fatal(err)
+	}
+	if role.Permissions != discordbot.PermissionModerateMembers {
+		t.Errorf("unexpected permissions %d", role.Permissions)
+	}
+
+	if red, green, blue := role.Color.RGB(); red != 0x34 || green != 0x98 || blue != 0xdb || role.Color.Hex() != "#3498db" {
+		t.Errorf("unexpected color %v", role.Color.Hex())
+	}
+
+	if color, err := discordbot.ParseColor("#3498DB"); err != nil || color != discordbot.NewColor(0x34, 0x98, 0xdb) {
+		t.Errorf("unexpected parsed color %v: %v", color, err)
+	}
+}
+
+func TestCreateGuildRole(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"id": "2", "name": "mods", "permissions": 8192}
+	})
+
+	settings := discordbot.RolePatch{}
+	settings.SetName("mods").SetPermissions(discordbot.PermissionManageMessages).SetHoist(true)
+
+	client := api.client(t)
+	role, err := client.CreateGuildRole(1, settings, "")
+
+	if err != nil || role.Permissions != discordbot.PermissionManageMessages {
+		t.Fatalf("unexpected role %+v: %v", role, err)
+	}
+
+	request := api.single(t)
+	if request.Path != "/guilds/1/roles" || string(request.Body) != `{"hoist":true,"name":"mods","permissions":8192}` {
+		t.Errorf("unexpected request %s %s", request.Path, request.Body)
+	}
+}
@@ -0,0 +1,120 @@
+package discordbot
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Unique ID used by Discord for guilds, channels, users, messages, etc. Serialized as a string in JSON.
+// Reference: https://discordapp.com/developers/docs/reference#snowflakes
+type Snowflake uint64
+
+// First second of 2015 in milliseconds since the unix epoch.
+const discordEpoch = 1420070400000
+
+const (
+	snowflakeTimestampShift = 22
+	snowflakeWorkerShift    = 17
+	snowflakeWorkerMask     = 0x3E0000
+	snowflakeProcessShift   = 12
+	snowflakeProcessMask    = 0x1F000
+	snowflakeIncrementMask  = 0xFFF
+)
+
+// Parses a snowflake from its decimal string form.
+func ParseSnowflake(s string) (Snowflake, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid snowflake [%s]: %v", s, err)
+	}
+
+	return Snowflake(id), nil
+}
+
+// Returns the smallest snowflake that could have been created at the given time. Useful as the
+// before or after bound of paginated queries.
+func SnowflakeFromTime(t time.Time) Snowflake {
+	ms := t.UnixNano()/int64(time.Millisecond) - discordEpoch
+
+	if ms < 0 {
+		return 0
+	}
+
+	return Snowflake(uint64(ms) << snowflakeTimestampShift)
+}
+
+func (s Snowflake) String() string {
+	return strconv.FormatUint(uint64(s), 10)
+}
+
+// Time the snowflake was created.
+func (s Snowflake) Time() time.Time {
+	ms := int64(s>>snowflakeTimestampShift) + discordEpoch
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// Internal worker ID that generated the snowflake.
+func (s Snowflake) WorkerId() uint8 {
+	return uint8((s & snowflakeWorkerMask) >> snowflakeWorkerShift)
+}
+
+// Internal process ID that generated the snowflake.
+func (s Snowflake) ProcessId() uint8 {
+	return uint8((s & snowflakeProcessMask) >> snowflakeProcessShift)
+}
+
+// Incremented for every snowflake generated by the process.
+func (s Snowflake) Increment() uint16 {
+	return uint16(s & snowflakeIncrementMask)
+}
+
+// Returns -1, 0 or 1 if the snowflake is less than, equal to or greater than other.
+// Snowflakes sort by creation time.
+func (s Snowflake) Compare(other Snowflake) int {
+	switch {
+	case s < other:
+		return -1
+	case s > other:
+		return 1
+	}
+	return 0
+}
+
+func (s Snowflake) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(s.String())), nil
+}
+
+// Accepts the usual string form as well as plain numbers. Null leaves the snowflake unchanged.
+func (s *Snowflake) UnmarshalJSON(data []byte) (err error) {
+	if bytes.Equal(data, []byte("null")) {
+		return
+	}
+
+	text := string(data)
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		text = string(data[1 : len(data)-1])
+	}
+
+	*s, err = ParseSnowflake(text)
+	return
+}
+
+// Text forms allow snowflakes to be used as JSON object keys.
+func (s Snowflake) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Snowflake) UnmarshalText(text []byte) (err error) {
+	*s, err = ParseSnowflake(string(text))
+	return
+}
+
+// Sorts snowflakes in ascending order, i.e. oldest first.
+type Snowflakes []Snowflake
+
+func (s Snowflakes) Len() int           { return len(s) }
+func (s Snowflakes) Less(i, j int) bool { return s[i] < s[j] }
+func (s Snowflakes) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,87 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+// Example from https://discordapp.com/developers/docs/reference#snowflakes
+const exampleSnowflake discordbot.Snowflake = 175928847299117063
+
+func TestSnowflakeFields(t *testing.T) {
+	expectedTime := time.Date(2016, time.April, 30, 11, 18, 25, 796*int(time.Millisecond), time.UTC)
+	if created := exampleSnowflake.Time(); !created.Equal(expectedTime) {
+		t.Errorf("expected time [%v], got [%v]", expectedTime, created.UTC())
+	}
+
+	if worker := exampleSnowflake.WorkerId(); worker != 1 {
+		t.Errorf("expected worker 1, got [%d]", worker)
+	}
+	if process := exampleSnowflake.ProcessId(); process != 0 {
+		t.Errorf("expected process 0, got [%d]", process)
+	}
+	if increment := exampleSnowflake.Increment(); increment != 7 {
+		t.Errorf("expected increment 7, got [%d]", increment)
+	}
+}
+
+func TestSnowflakeJson(t *testing.T) {
+	holder := struct {
+		Id       discordbot.Snowflake  `json:"id"`
+		Number   discordbot.Snowflake  `json:"number"`
+		Optional *discordbot.Snowflake `json:"optional"`
+	}{}
+
+	err := json.Unmarshal([]byte(`{"id":"175928847299117063","number":175928847299117063,"optional":null}`), &holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if holder.Id != exampleSnowflake || holder.Number != exampleSnowflake || holder.Optional != nil {
+		t.Errorf("unexpected decode: %+v", holder)
+	}
+
+	encoded, err := json.Marshal(holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"id":"175928847299117063","number":"175928847299117063","optional":null}`
+	if string(encoded) != expected {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+
+	if err := json.Unmarshal([]byte(`{"id":"not a number"}`), &holder); err == nil {
+		t.Error("expected error decoding invalid snowflake")
+	}
+}
+
+func TestSnowflakeOrdering(t *testing.T) {
+	snowflakes := discordbot.Snowflakes{exampleSnowflake, 1, exampleSnowflake - 1}
+	sort.Sort(snowflakes)
+
+	if snowflakes[0] != 1 || snowflakes[2] != exampleSnowflake {
+		t.Errorf("not sorted: %v", snowflakes)
+	}
+
+	if exampleSnowflake.Compare(1) != 1 || discordbot.Snowflake(1).Compare(exampleSnowflake) != -1 ||
+		exampleSnowflake.Compare(exampleSnowflake) != 0 {
+		t.Error("unexpected comparison")
+	}
+}
+
+func TestSnowflakeFromTime(t *testing.T) {
+	bound := discordbot.SnowflakeFromTime(exampleSnowflake.Time())
+
+	if bound > exampleSnowflake || !bound.Time().Equal(exampleSnowflake.Time()) {
+		t.Errorf("bound [%v] should be at or before [%v] with the same time", bound, exampleSnowflake)
+	}
+
+	if discordbot.SnowflakeFromTime(time.Unix(0, 0)) != 0 {
+		t.Error("times before the discord epoch should give 0")
+	}
+}
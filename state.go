@@ -0,0 +1,805 @@
+package discordbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Which kinds of data a State keeps.
+type StateOptions struct {
+	Guilds      bool
+	Channels    bool
+	Members     bool
+	Roles       bool
+	Presences   bool
+	VoiceStates bool
+	// Number of recent messages kept per channel. 0 disables the message cache.
+	MaxMessagesPerChannel int
+	// Where the cached data is kept. Defaults to an in-memory LRU store of DefaultCacheSize entries.
+	Store CacheStore
+}
+
+// Caches everything except presences, and the last 100 messages per channel, in memory.
+func DefaultStateOptions() StateOptions {
+	return StateOptions{
+		Guilds:                true,
+		Channels:              true,
+		Members:               true,
+		Roles:                 true,
+		Presences:             false,
+		VoiceStates:           true,
+		MaxMessagesPerChannel: 100,
+	}
+}
+
+// Buckets of the State's CacheStore. Channels, members, roles, presences and voice states are
+// keyed by guild ID and then their own ID, with private channels under guild 0, so that a guild's
+// values can be scanned by prefix. Guilds are stored without their channels, members, roles,
+// presences and voice states. Messages are keyed by channel ID and message ID, with the IDs of
+// each channel's cached messages kept as a list, oldest first.
+const (
+	stateBucketUser          = "user"
+	stateBucketGuilds        = "guilds"
+	stateBucketChannels      = "channels"
+	stateBucketChannelGuilds = "channel_guilds"
+	stateBucketMembers       = "members"
+	stateBucketRoles         = "roles"
+	stateBucketPresences     = "presences"
+	stateBucketVoiceStates   = "voice_states"
+	stateBucketMessages      = "messages"
+	stateBucketMessageIds    = "message_ids"
+)
+
+// Key of the current user in the user bucket.
+const stateCurrentUserKey = "@me"
+
+// Cache of guilds, channels, members etc. kept up to date from gateway events.
+// Set it as a gateway's StateCache before connecting. Lookups are safe for concurrent use and
+// decode a fresh value from the store each time, so callers may modify the results, including
+// their slices, without affecting the cache. A store that evicts entries may leave a guild's
+// roles or channels incomplete.
+type State struct {
+	Options StateOptions
+
+	// Held while reading and writing the store so that updates applied over cached values are atomic.
+	mutex sync.RWMutex
+	store CacheStore
+	// Writes of the events being ingested, in order, and the index of the last write of each key.
+	pending     []CacheWrite
+	pendingKeys map[pendingKey]int
+}
+
+type pendingKey struct {
+	bucket string
+	key    string
+}
+
+// A State using options.Store starts with whatever that store already holds.
+func NewState(options StateOptions) *State {
+	store := options.Store
+	if store == nil {
+		store = NewMemoryCacheStore(DefaultCacheSize)
+	}
+
+	return &State{Options: options, store: store}
+}
+
+// Closes the underlying store.
+func (s *State) Close() error {
+	return s.store.Close()
+}
+
+// Current user, from READY and USER_UPDATE.
+func (s *State) User() (user User, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ok = s.get(stateBucketUser, stateCurrentUserKey, &user)
+	return
+}
+
+// Returns the guild with its roles and channels. Members, presences and voice states are
+// looked up individually.
+func (s *State) Guild(guildId Snowflake) (guild Guild, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if ok = s.get(stateBucketGuilds, guildId.String(), &guild); !ok {
+		return
+	}
+
+	guild.Roles = []Role{}
+	s.scan(stateBucketRoles, guildKeyPrefix(guildId), func(value []byte) error {
+		role := Role{}
+		err := json.Unmarshal(value, &role)
+		if err == nil {
+			guild.Roles = append(guild.Roles, role)
+		}
+		return err
+	})
+
+	channels := []Channel{}
+	s.scan(stateBucketChannels, guildKeyPrefix(guildId), func(value []byte) error {
+		channel := Channel{}
+		err := json.Unmarshal(value, &channel)
+		if err == nil {
+			channels = append(channels, channel)
+		}
+		return err
+	})
+	guild.Channels = &channels
+
+	return
+}
+
+// IDs of all guilds the current user is in, including unavailable ones.
+func (s *State) GuildIds() (guildIds []Snowflake) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	err := s.storeScan(stateBucketGuilds, "", func(key string, value []byte) {
+		if guildId, err := ParseSnowflake(key); err == nil {
+			guildIds = append(guildIds, guildId)
+		}
+	})
+
+	if err != nil {
+		log.Print("State failed to list guilds: ", err)
+	}
+	return
+}
+
+func (s *State) Channel(channelId Snowflake) (channel Channel, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var guildId Snowflake
+	ok = s.get(stateBucketChannelGuilds, channelId.String(), &guildId) &&
+		s.get(stateBucketChannels, guildKey(guildId, channelId), &channel)
+	return
+}
+
+func (s *State) Member(guildId Snowflake, userId Snowflake) (member GuildMember, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ok = s.get(stateBucketMembers, guildKey(guildId, userId), &member)
+	return
+}
+
+func (s *State) Role(guildId Snowflake, roleId Snowflake) (role Role, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ok = s.get(stateBucketRoles, guildKey(guildId, roleId), &role)
+	return
+}
+
+func (s *State) Presence(guildId Snowflake, userId Snowflake) (presence Presence, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ok = s.get(stateBucketPresences, guildKey(guildId, userId), &presence)
+	return
+}
+
+// Voice state of a user connected to a voice channel in the guild.
+func (s *State) VoiceState(guildId Snowflake, userId Snowflake) (voiceState VoiceState, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ok = s.get(stateBucketVoiceStates, guildKey(guildId, userId), &voiceState)
+	return
+}
+
+func (s *State) Message(channelId Snowflake, messageId Snowflake) (message Message, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ok = s.get(stateBucketMessages, messageKey(channelId, messageId), &message)
+	return
+}
+
+// Cached messages for the channel, oldest first.
+func (s *State) Messages(channelId Snowflake) (messages []Message) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	messageIds := []Snowflake{}
+	s.get(stateBucketMessageIds, channelId.String(), &messageIds)
+
+	for _, messageId := range messageIds {
+		message := Message{}
+		if s.get(stateBucketMessages, messageKey(channelId, messageId), &message) {
+			messages = append(messages, message)
+		}
+	}
+	return
+}
+
+// Called by the gateway with dispatches in the order they were received, before their event
+// listeners, so that listeners see the updated state. The writes of all the events are applied
+// to the store together, so a batch costs one store transaction however many events it holds.
+func (s *State) ingest(payloads ...GatewayPayload) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	defer s.flush()
+
+	for _, payload := range payloads {
+		s.apply(payload)
+	}
+}
+
+func (s *State) apply(payload GatewayPayload) {
+	var err error
+
+	switch payload.EventName {
+	case EventReady:
+		ready := gatewayReadyResponse{}
+		if err = json.Unmarshal(payload.EventData, &ready); err == nil {
+			s.ready(ready)
+		}
+	case EventUserUpdate:
+		user := User{}
+		if err = json.Unmarshal(payload.EventData, &user); err == nil {
+			s.put(stateBucketUser, stateCurrentUserKey, user)
+		}
+	case EventGuildCreate:
+		guild := Guild{}
+		if err = json.Unmarshal(payload.EventData, &guild); err == nil {
+			s.guildCreate(guild)
+		}
+	case EventGuildUpdate:
+		err = s.guildUpdate(payload.EventData)
+	case EventGuildEmojisUpdate:
+		update := GuildEmojisUpdate{}
+		if err = json.Unmarshal(payload.EventData, &update); err == nil {
+			s.updateGuild(update.GuildId, func(guild *Guild) { guild.Emojis = update.Emojis })
+		}
+	case EventGuildStickersUpdate:
+		update := GuildStickersUpdate{}
+		if err = json.Unmarshal(payload.EventData, &update); err == nil {
+			s.updateGuild(update.GuildId, func(guild *Guild) { guild.Stickers = update.Stickers })
+		}
+	case EventGuildDelete:
+		guild := UnavailableGuild{}
+		if err = json.Unmarshal(payload.EventData, &guild); err == nil {
+			s.guildDelete(guild)
+		}
+	case EventChannelCreate, EventChannelUpdate:
+		channel := Channel{}
+		if err = json.Unmarshal(payload.EventData, &channel); err == nil {
+			s.putChannel(channel)
+		}
+	case EventChannelDelete:
+		channel := Channel{}
+		if err = json.Unmarshal(payload.EventData, &channel); err == nil {
+			s.removeChannel(channel.Id)
+		}
+	case EventGuildMemberAdd:
+		member := GuildMemberAdd{}
+		if err = json.Unmarshal(payload.EventData, &member); err == nil {
+			s.putMember(member.GuildId, member.GuildMember)
+		}
+	case EventGuildMemberUpdate:
+		update := GuildMemberUpdate{}
+		if err = json.Unmarshal(payload.EventData, &update); err == nil {
+			s.memberUpdate(update)
+		}
+	case EventGuildMemberRemove:
+		remove := GuildMemberRemove{}
+		if err = json.Unmarshal(payload.EventData, &remove); err == nil {
+			s.remove(stateBucketMembers, guildKey(remove.GuildId, remove.User.Id))
+		}
+	case EventGuildMembersChunk:
+		chunk := gatewayGuildMembersChunk{}
+		if err = json.Unmarshal(payload.EventData, &chunk); err == nil {
+			for _, member := range chunk.Members {
+				s.putMember(chunk.GuildId, member)
+			}
+			for _, presence := range chunk.Presences {
+				s.putPresence(chunk.GuildId, presence)
+			}
+		}
+	case EventGuildRoleCreate, EventGuildRoleUpdate:
+		update := GuildRoleUpdate{}
+		if err = json.Unmarshal(payload.EventData, &update); err == nil {
+			s.putRole(update.GuildId, update.Role)
+		}
+	case EventGuildRoleDelete:
+		remove := GuildRoleDelete{}
+		if err = json.Unmarshal(payload.EventData, &remove); err == nil {
+			s.remove(stateBucketRoles, guildKey(remove.GuildId, remove.RoleId))
+		}
+	case EventPresenceUpdate:
+		presence := Presence{}
+		if err = json.Unmarshal(payload.EventData, &presence); err == nil && presence.GuildId != nil {
+			s.putPresence(*presence.GuildId, presence)
+		}
+	case EventVoiceStateUpdate:
+		voiceState := VoiceState{}
+		if err = json.Unmarshal(payload.EventData, &voiceState); err == nil && voiceState.GuildId != nil {
+			s.putVoiceState(*voiceState.GuildId, voiceState)
+		}
+	case EventMessageCreate:
+		message := Message{}
+		if err = json.Unmarshal(payload.EventData, &message); err == nil {
+			s.messageCreate(message)
+		}
+	case EventMessageUpdate:
+		err = s.messageUpdate(payload.EventData)
+	case EventMessageDelete:
+		remove := MessageDelete{}
+		if err = json.Unmarshal(payload.EventData, &remove); err == nil {
+			s.messageDelete(remove.ChannelId, remove.Id)
+		}
+	case EventMessageDeleteBulk:
+		remove := MessageDeleteBulk{}
+		if err = json.Unmarshal(payload.EventData, &remove); err == nil {
+			s.messageDelete(remove.ChannelId, remove.Ids...)
+		}
+	}
+
+	if err != nil {
+		log.Printf("State failed to handle event [%s]: %v", payload.EventName, err)
+	}
+}
+
+func (s *State) ready(ready gatewayReadyResponse) {
+	s.put(stateBucketUser, stateCurrentUserKey, ready.User)
+
+	// Presences and voice states from before a new session, or kept by the store across restarts,
+	// are out of date. Current ones are sent in GUILD_CREATE.
+	s.removePrefix(stateBucketPresences, "")
+	s.removePrefix(stateBucketVoiceStates, "")
+
+	// Guilds left while disconnected, or since a store was last used, aren't in READY.
+	listed := make(map[Snowflake]bool)
+	for _, unavailable := range ready.Guilds {
+		listed[unavailable.Id] = true
+	}
+
+	for guildId := range s.cachedGuildIds() {
+		if !listed[guildId] && guildId != 0 {
+			s.guildDelete(UnavailableGuild{Id: guildId})
+		}
+	}
+
+	if s.Options.Guilds {
+		for _, unavailable := range ready.Guilds {
+			isUnavailable := unavailable.Unavailable
+			guild := Guild{Id: unavailable.Id}
+			s.get(stateBucketGuilds, unavailable.Id.String(), &guild)
+			guild.Unavailable = &isUnavailable
+			s.put(stateBucketGuilds, unavailable.Id.String(), guild)
+		}
+	}
+
+	for _, channel := range ready.PrivateChannels {
+		s.putChannel(channel)
+	}
+}
+
+// IDs of guilds with any cached data, including guilds that are only known from their channels,
+// members or roles when guilds aren't cached.
+func (s *State) cachedGuildIds() map[Snowflake]bool {
+	guildIds := make(map[Snowflake]bool)
+
+	for _, bucket := range []string{stateBucketGuilds, stateBucketChannels, stateBucketMembers, stateBucketRoles} {
+		err := s.storeScan(bucket, "", func(key string, value []byte) {
+			if i := strings.Index(key, "/"); i >= 0 {
+				key = key[:i]
+			}
+			if guildId, err := ParseSnowflake(key); err == nil {
+				guildIds[guildId] = true
+			}
+		})
+
+		if err != nil {
+			log.Printf("State failed to list [%s]: %v", bucket, err)
+		}
+	}
+	return guildIds
+}
+
+func (s *State) guildCreate(guild Guild) {
+	if guild.Channels != nil {
+		for _, channel := range *guild.Channels {
+			// Channels in GUILD_CREATE don't include the guild ID.
+			guildId := guild.Id
+			channel.GuildId = &guildId
+			s.putChannel(channel)
+		}
+	}
+
+	for _, role := range guild.Roles {
+		s.putRole(guild.Id, role)
+	}
+
+	for _, member := range guild.Members {
+		s.putMember(guild.Id, member)
+	}
+
+	for _, presence := range guild.Presences {
+		s.putPresence(guild.Id, presence)
+	}
+
+	for _, voiceState := range guild.VoiceStates {
+		s.putVoiceState(guild.Id, voiceState)
+	}
+
+	s.putGuild(guild)
+}
+
+// GUILD_UPDATE only has some of the GUILD_CREATE fields, so it is applied over the cached guild.
+func (s *State) guildUpdate(data json.RawMessage) (err error) {
+	update := Guild{}
+	err = json.Unmarshal(data, &update)
+
+	if err != nil || !s.Options.Guilds {
+		return
+	}
+
+	cached := Guild{}
+	s.get(stateBucketGuilds, update.Id.String(), &cached)
+
+	guild := Guild{}
+	err = mergeJson(&guild, cached, data)
+
+	if err != nil {
+		return
+	}
+
+	for _, role := range update.Roles {
+		s.putRole(guild.Id, role)
+	}
+
+	s.putGuild(guild)
+	return
+}
+
+func (s *State) guildDelete(guild UnavailableGuild) {
+	// Unavailable guilds will be sent again in GUILD_CREATE once the outage is over.
+	if guild.Unavailable {
+		cached := Guild{}
+		if s.get(stateBucketGuilds, guild.Id.String(), &cached) {
+			cached.Unavailable = &guild.Unavailable
+			s.put(stateBucketGuilds, guild.Id.String(), cached)
+		}
+		return
+	}
+
+	s.remove(stateBucketGuilds, guild.Id.String())
+	s.removePrefix(stateBucketMembers, guildKeyPrefix(guild.Id))
+	s.removePrefix(stateBucketRoles, guildKeyPrefix(guild.Id))
+	s.removePrefix(stateBucketPresences, guildKeyPrefix(guild.Id))
+	s.removePrefix(stateBucketVoiceStates, guildKeyPrefix(guild.Id))
+
+	channelIds := []Snowflake{}
+	err := s.storeScan(stateBucketChannels, guildKeyPrefix(guild.Id), func(key string, value []byte) {
+		if channelId, err := ParseSnowflake(strings.TrimPrefix(key, guildKeyPrefix(guild.Id))); err == nil {
+			channelIds = append(channelIds, channelId)
+		}
+	})
+
+	if err != nil {
+		log.Printf("State failed to list channels of guild [%s]: %v", guild.Id, err)
+	}
+
+	for _, channelId := range channelIds {
+		s.removeChannel(channelId)
+	}
+}
+
+// Removes the channel and its messages.
+func (s *State) removeChannel(channelId Snowflake) {
+	var guildId Snowflake
+	if s.get(stateBucketChannelGuilds, channelId.String(), &guildId) {
+		s.remove(stateBucketChannels, guildKey(guildId, channelId))
+		s.remove(stateBucketChannelGuilds, channelId.String())
+	}
+
+	messageIds := []Snowflake{}
+	s.get(stateBucketMessageIds, channelId.String(), &messageIds)
+
+	for _, messageId := range messageIds {
+		s.remove(stateBucketMessages, messageKey(channelId, messageId))
+	}
+	s.remove(stateBucketMessageIds, channelId.String())
+}
+
+func (s *State) memberUpdate(update GuildMemberUpdate) {
+	member := GuildMember{}
+	if !s.get(stateBucketMembers, guildKey(update.GuildId, update.User.Id), &member) {
+		return
+	}
+
+	member.User = update.User
+	member.Roles = update.Roles
+	member.Nick = update.Nick
+	member.PremiumSince = update.PremiumSince
+	s.putMember(update.GuildId, member)
+}
+
+func (s *State) messageCreate(message Message) {
+	max := s.Options.MaxMessagesPerChannel
+	if max <= 0 {
+		return
+	}
+
+	messageIds := []Snowflake{}
+	s.get(stateBucketMessageIds, message.ChannelId.String(), &messageIds)
+
+	messageIds = append(messageIds, message.Id)
+	if len(messageIds) > max {
+		for _, messageId := range messageIds[:len(messageIds)-max] {
+			s.remove(stateBucketMessages, messageKey(message.ChannelId, messageId))
+		}
+		messageIds = messageIds[len(messageIds)-max:]
+	}
+
+	s.put(stateBucketMessageIds, message.ChannelId.String(), messageIds)
+	s.put(stateBucketMessages, messageKey(message.ChannelId, message.Id), message)
+}
+
+// MESSAGE_UPDATE may only contain some fields, so it is applied over the cached message.
+func (s *State) messageUpdate(data json.RawMessage) (err error) {
+	update := MessageDelete{}
+	err = json.Unmarshal(data, &update)
+
+	if err != nil {
+		return
+	}
+
+	cached := Message{}
+	if !s.get(stateBucketMessages, messageKey(update.ChannelId, update.Id), &cached) {
+		return
+	}
+
+	message := Message{}
+	err = mergeJson(&message, cached, data)
+
+	if err == nil {
+		s.put(stateBucketMessages, messageKey(update.ChannelId, update.Id), message)
+	}
+	return
+}
+
+func (s *State) messageDelete(channelId Snowflake, messageIds ...Snowflake) {
+	cachedIds := []Snowflake{}
+	if !s.get(stateBucketMessageIds, channelId.String(), &cachedIds) {
+		return
+	}
+
+	deleted := make(map[Snowflake]bool)
+	for _, messageId := range messageIds {
+		deleted[messageId] = true
+	}
+
+	remaining := []Snowflake{}
+	for _, messageId := range cachedIds {
+		if deleted[messageId] {
+			s.remove(stateBucketMessages, messageKey(channelId, messageId))
+		} else {
+			remaining = append(remaining, messageId)
+		}
+	}
+	s.put(stateBucketMessageIds, channelId.String(), remaining)
+}
+
+// The put functions must be called with the mutex held.
+
+func (s *State) putGuild(guild Guild) {
+	if !s.Options.Guilds {
+		return
+	}
+
+	guild.Channels = nil
+	guild.Roles = nil
+	guild.Members = nil
+	guild.Presences = nil
+	guild.VoiceStates = nil
+	s.put(stateBucketGuilds, guild.Id.String(), guild)
+}
+
+func (s *State) putChannel(channel Channel) {
+	if !s.Options.Channels {
+		return
+	}
+
+	var guildId Snowflake
+	if channel.GuildId != nil {
+		guildId = *channel.GuildId
+	}
+
+	s.put(stateBucketChannels, guildKey(guildId, channel.Id), channel)
+	s.put(stateBucketChannelGuilds, channel.Id.String(), guildId)
+}
+
+func (s *State) putMember(guildId Snowflake, member GuildMember) {
+	if s.Options.Members {
+		s.put(stateBucketMembers, guildKey(guildId, member.User.Id), member)
+	}
+}
+
+func (s *State) putRole(guildId Snowflake, role Role) {
+	if s.Options.Roles {
+		s.put(stateBucketRoles, guildKey(guildId, role.Id), role)
+	}
+}
+
+func (s *State) putPresence(guildId Snowflake, presence Presence) {
+	if s.Options.Presences {
+		s.put(stateBucketPresences, guildKey(guildId, presence.User.Id), presence)
+	}
+}
+
+// Applies the update to the cached guild, if any.
+func (s *State) updateGuild(guildId Snowflake, update func(guild *Guild)) {
+	guild := Guild{}
+	if s.Options.Guilds && s.get(stateBucketGuilds, guildId.String(), &guild) {
+		update(&guild)
+		s.putGuild(guild)
+	}
+}
+
+// Users that leave voice are removed.
+func (s *State) putVoiceState(guildId Snowflake, voiceState VoiceState) {
+	if !s.Options.VoiceStates {
+		return
+	}
+
+	if voiceState.ChannelId == nil {
+		s.remove(stateBucketVoiceStates, guildKey(guildId, voiceState.UserId))
+		return
+	}
+
+	s.put(stateBucketVoiceStates, guildKey(guildId, voiceState.UserId), voiceState)
+}
+
+// Store access. Failures are logged, and a value that can't be read is treated as missing.
+// Writes are queued until the events being ingested have been applied, and reads see the queued
+// writes over the store.
+
+func (s *State) get(bucket string, key string, value interface{}) bool {
+	valueJsonBytes, ok, err := s.storeGet(bucket, key)
+
+	if err == nil && ok {
+		err = json.Unmarshal(valueJsonBytes, value)
+	}
+
+	if err != nil {
+		log.Printf("State failed to read [%s/%s]: %v", bucket, key, err)
+		return false
+	}
+
+	return ok
+}
+
+func (s *State) put(bucket string, key string, value interface{}) {
+	valueJsonBytes, err := json.Marshal(value)
+
+	if err != nil {
+		log.Printf("State failed to write [%s/%s]: %v", bucket, key, err)
+		return
+	}
+
+	s.queue(CacheWrite{Bucket: bucket, Key: key, Value: valueJsonBytes})
+}
+
+func (s *State) remove(bucket string, key string) {
+	s.queue(CacheWrite{Bucket: bucket, Key: key})
+}
+
+func (s *State) queue(write CacheWrite) {
+	if s.pendingKeys == nil {
+		s.pendingKeys = make(map[pendingKey]int)
+	}
+
+	s.pendingKeys[pendingKey{write.Bucket, write.Key}] = len(s.pending)
+	s.pending = append(s.pending, write)
+}
+
+func (s *State) storeGet(bucket string, key string) (value []byte, ok bool, err error) {
+	if i, queued := s.pendingKeys[pendingKey{bucket, key}]; queued {
+		value = s.pending[i].Value
+		return value, value != nil, nil
+	}
+
+	return s.store.Get(bucket, key)
+}
+
+// Scans the store with the queued writes applied. Queued values come after the stored ones.
+func (s *State) storeScan(bucket string, prefix string, fn func(key string, value []byte)) error {
+	var queued []CacheWrite
+	for key, i := range s.pendingKeys {
+		if key.bucket == bucket && strings.HasPrefix(key.key, prefix) && s.pending[i].Value != nil {
+			queued = append(queued, s.pending[i])
+		}
+	}
+
+	err := s.store.Scan(bucket, prefix, func(key string, value []byte) {
+		if _, ok := s.pendingKeys[pendingKey{bucket, key}]; !ok {
+			fn(key, value)
+		}
+	})
+
+	for _, write := range queued {
+		fn(write.Key, write.Value)
+	}
+	return err
+}
+
+func (s *State) removePrefix(bucket string, prefix string) {
+	err := s.storeScan(bucket, prefix, func(key string, value []byte) {
+		s.remove(bucket, key)
+	})
+
+	if err != nil {
+		log.Printf("State failed to list [%s/%s]: %v", bucket, prefix, err)
+	}
+}
+
+func (s *State) flush() {
+	if len(s.pending) == 0 {
+		return
+	}
+
+	if err := s.store.Write(s.pending); err != nil {
+		log.Printf("State failed to write %d changes: %v", len(s.pending), err)
+	}
+	s.pending = nil
+	s.pendingKeys = nil
+}
+
+// Calls fn with every value in the bucket under the prefix, stopping at the first error.
+func (s *State) scan(bucket string, prefix string, fn func(value []byte) error) {
+	var fnErr error
+	err := s.storeScan(bucket, prefix, func(key string, value []byte) {
+		if fnErr == nil {
+			fnErr = fn(value)
+		}
+	})
+
+	if err == nil {
+		err = fnErr
+	}
+
+	if err != nil {
+		log.Printf("State failed to read [%s/%s]: %v", bucket, prefix, err)
+	}
+}
+
+// Key of a value that belongs to a guild, such as a member or role.
+func guildKey(guildId Snowflake, id Snowflake) string {
+	return guildKeyPrefix(guildId) + id.String()
+}
+
+func guildKeyPrefix(guildId Snowflake) string {
+	return guildId.String() + "/"
+}
+
+func messageKey(channelId Snowflake, messageId Snowflake) string {
+	return channelId.String() + "/" + messageId.String()
+}
+
+// Decodes base and then the partial update into dst, which must be a fresh value so that
+// nothing is shared with base.
+func mergeJson(dst interface{}, base interface{}, update json.RawMessage) error {
+	baseJsonBytes, err := json.Marshal(base)
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached value: %v", err)
+	}
+
+	err = json.Unmarshal(baseJsonBytes, dst)
+
+	if err != nil {
+		return fmt.Errorf("failed to copy cached value: %v", err)
+	}
+
+	return json.Unmarshal(update, dst)
+}
@@ -0,0 +1,234 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+// Dispatches events to a gateway with a state cache and waits until they have been handled.
+func dispatchToState(t *testing.T, fake *fakeGateway, gateway *discordbot.DiscordGateway, events ...interface{}) {
+	for i := 0; i < len(events); i += 2 {
+		fake.dispatch(t, events[i].(string), events[i+1])
+	}
+
+	// Listeners are called after the state is updated, so a listener on a final event marks completion.
+	done := make(chan bool, 1)
+	gateway.RegisterEventListener(discordbot.EventWebhooksUpdate, func(discordbot.GatewayPayload) {
+		select {
+		case done <- true:
+		default:
+		}
+	})
+	fake.dispatch(t, discordbot.EventWebhooksUpdate, map[string]string{})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("events were not handled")
+	}
+}
+
+func TestStateCache(t *testing.T) {
+	options := discordbot.DefaultStateOptions()
+	options.Presences = true
+	options.MaxMessagesPerChannel = 2
+	state := discordbot.NewState(options)
+
+	fake := newFakeGateway(t)
+	gateway := &discordbot.DiscordGateway{
+		GatewayInfo: discordbot.GatewayInfo{Url: fake.url()},
+		StateCache:  state,
+	}
+	if err := gateway.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	fake.conn = <-fake.conns
+
+	guildCreate := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(guildCreatePayload), &guildCreate); err != nil {
+		t.Fatal(err)
+	}
+
+	const guildId = 41771983423143937
+	const userId = 80351110224678912
+	dispatchToState(t, fake, gateway,
+		discordbot.EventReady, map[string]interface{}{
+			"v": 6, "session_id": "session", "user": map[string]string{"id": "1", "username": "bot"},
+			"guilds": []map[string]interface{}{{"id": "41771983423143937", "unavailable": true}},
+		},
+		discordbot.EventGuildCreate, guildCreate,
+	)
+
+	if user, ok := state.User(); !ok || user.Username != "bot" {
+		t.Errorf("unexpected current user: %+v", user)
+	}
+
+	guild, ok := state.Guild(guildId)
+	if !ok || guild.Name != "Discord Developers" || len(guild.Roles) != 1 || len(*guild.Channels) != 1 {
+		t.Fatalf("unexpected guild: %+v", guild)
+	}
+
+	guild.Roles[0].Name = "changed"
+	guild.Roles = append(guild.Roles, discordbot.Role{Id: 3})
+	if cached, _ := state.Guild(guildId); len(cached.Roles) != 1 || cached.Roles[0].Name == "changed" {
+		t.Errorf("modifying a lookup result changed the cache: %+v", cached.Roles)
+	}
+
+	if channel, ok := state.Channel(guildId); !ok || channel.GuildId == nil || *channel.GuildId != guildId {
+		t.Errorf("channel not cached with its guild: %+v", channel)
+	}
+
+	if _, ok := state.Member(guildId, userId); !ok {
+		t.Error("member not cached")
+	}
+
+	if _, ok := state.Presence(guildId, userId); !ok {
+		t.Error("presence not cached")
+	}
+
+	if _, ok := state.VoiceState(guildId, userId); !ok {
+		t.Error("voice state not cached")
+	}
+
+	dispatchToState(t, fake, gateway,
+		discordbot.EventGuildUpdate, map[string]interface{}{"id": "41771983423143937", "name": "Renamed"},
+		discordbot.EventGuildRoleCreate, map[string]interface{}{
+			"guild_id": "41771983423143937", "role": map[string]interface{}{"id": "2", "name": "mods"},
+		},
+		discordbot.EventGuildRoleDelete, map[string]interface{}{"guild_id": "41771983423143937", "role_id": "41771983423143937"},
+		discordbot.EventGuildMemberUpdate, map[string]interface{}{
+			"guild_id": "41771983423143937", "roles": []string{"2"}, "nick": "nelly",
+			"user": map[string]string{"id": "80351110224678912", "username": "Nelly"},
+		},
+		discordbot.EventVoiceStateUpdate, map[string]interface{}{
+			"guild_id": "41771983423143937", "channel_id": nil, "user_id": "80351110224678912",
+		},
+		discordbot.EventMessageCreate, map[string]interface{}{"id": "10", "channel_id": "41771983423143937", "content": "a"},
+		discordbot.EventMessageCreate, map[string]interface{}{"id": "11", "channel_id": "41771983423143937", "content": "b"},
+		discordbot.EventMessageCreate, map[string]interface{}{"id": "12", "channel_id": "41771983423143937", "content": "c"},
+		discordbot.EventMessageUpdate, map[string]interface{}{"id": "12", "channel_id": "41771983423143937", "content": "edited"},
+		discordbot.EventMessageDelete, map[string]interface{}{"id": "11", "channel_id": "41771983423143937"},
+		discordbot.EventGuildEmojisUpdate, map[string]interface{}{
+			"guild_id": "41771983423143937", "emojis": []map[string]string{{"id": "5", "name": "blob"}},
+		},
+	)
+
+	guild, _ = state.Guild(guildId)
+	if guild.Name != "Renamed" || guild.OwnerId != userId || len(*guild.Channels) != 1 {
+		t.Errorf("guild update not merged: %+v", guild)
+	}
+
+	if len(guild.Emojis) != 1 || guild.Emojis[0].Name != "blob" {
+		t.Errorf("emoji update not applied: %+v", guild.Emojis)
+	}
+
+	if _, ok := state.Role(guildId, guildId); ok {
+		t.Error("deleted role still cached")
+	}
+	if role, ok := state.Role(guildId, 2); !ok || role.Name != "mods" {
+		t.Errorf("created role not cached: %+v", role)
+	}
+
+	member, _ := state.Member(guildId, userId)
+	if member.Nick == nil || *member.Nick != "nelly" || len(member.Roles) != 1 || member.JoinedAt.IsZero() {
+		t.Errorf("member update not applied: %+v", member)
+	}
+
+	if _, ok := state.VoiceState(guildId, userId); ok {
+		t.Error("voice state not removed after leaving")
+	}
+
+	messages := state.Messages(guildId)
+	if len(messages) != 1 || messages[0].Id != 12 || messages[0].Content != "edited" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+
+	if message, ok := state.Message(guildId, 12); !ok || message.Content != "edited" {
+		t.Errorf("unexpected message: %+v", message)
+	}
+
+	// A new session's READY drops presences from the previous one until GUILD_CREATE resends them.
+	dispatchToState(t, fake, gateway,
+		discordbot.EventReady, map[string]interface{}{
+			"v": 6, "session_id": "session2", "user": map[string]string{"id": "1", "username": "bot"},
+		},
+	)
+
+	if _, ok := state.Presence(guildId, userId); ok {
+		t.Error("presence from the previous session still cached")
+	}
+
+	dispatchToState(t, fake, gateway,
+		discordbot.EventGuildDelete, map[string]interface{}{"id": "41771983423143937"},
+	)
+
+	if _, ok := state.Guild(guildId); ok {
+		t.Error("guild still cached after delete")
+	}
+	if _, ok := state.Channel(guildId); ok {
+		t.Error("channel still cached after guild delete")
+	}
+}
+
+func TestStateReadyDropsMissingGuilds(t *testing.T) {
+	store := discordbot.NewMemoryCacheStore(discordbot.DefaultCacheSize)
+	connect := func(ready map[string]interface{}, events ...interface{}) *discordbot.State {
+		options := discordbot.DefaultStateOptions()
+		options.Store = store
+		state := discordbot.NewState(options)
+
+		fake := newFakeGateway(t)
+		gateway := &discordbot.DiscordGateway{
+			GatewayInfo: discordbot.GatewayInfo{Url: fake.url()},
+			StateCache:  state,
+		}
+		if err := gateway.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		defer gateway.Close()
+		fake.conn = <-fake.conns
+
+		dispatchToState(t, fake, gateway, append([]interface{}{discordbot.EventReady, ready}, events...)...)
+		return state
+	}
+
+	guildCreate := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(guildCreatePayload), &guildCreate); err != nil {
+		t.Fatal(err)
+	}
+
+	const guildId = 41771983423143937
+	const userId = 80351110224678912
+	connect(map[string]interface{}{
+		"v": 6, "session_id": "session", "user": map[string]string{"id": "1", "username": "bot"},
+		"guilds": []map[string]interface{}{{"id": "41771983423143937", "unavailable": true}},
+	}, discordbot.EventGuildCreate, guildCreate)
+
+	// The bot left the guild while no state was using the store.
+	state := connect(map[string]interface{}{
+		"v": 6, "session_id": "session2", "user": map[string]string{"id": "1", "username": "bot"},
+		"guilds": []map[string]interface{}{{"id": "7", "unavailable": true}},
+	})
+
+	if guildIds := state.GuildIds(); len(guildIds) != 1 || guildIds[0] != 7 {
+		t.Errorf("unexpected guilds: %v", guildIds)
+	}
+	if guild, ok := state.Guild(7); !ok || guild.Unavailable == nil || !*guild.Unavailable {
+		t.Errorf("listed guild not cached as unavailable: %+v", guild)
+	}
+	if _, ok := state.Guild(guildId); ok {
+		t.Error("guild missing from READY still cached")
+	}
+	if _, ok := state.Channel(guildId); ok {
+		t.Error("channel of a guild missing from READY still cached")
+	}
+	if _, ok := state.Member(guildId, userId); ok {
+		t.Error("member of a guild missing from READY still cached")
+	}
+	if _, ok := state.Role(guildId, guildId); ok {
+		t.Error("role of a guild missing from READY still cached")
+	}
+}
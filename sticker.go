@@ -0,0 +1,199 @@
+package discordbot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/sticker#sticker-object-sticker-structure
+type Sticker struct {
+	Id Snowflake `json:"id"`
+	// Only set for standard stickers.
+	PackId      *Snowflake `json:"pack_id,omitempty"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	// Comma separated autocomplete keywords. For guild stickers, the name of the related unicode emoji.
+	Tags       string `json:"tags"`
+	Type       int    `json:"type"`
+	FormatType int    `json:"format_type"`
+	// False for guild stickers that were disabled when the guild lost boosts.
+	Available *bool      `json:"available,omitempty"`
+	GuildId   *Snowflake `json:"guild_id,omitempty"`
+	// Uploader, only set when requested with the manage emojis permission.
+	User      *User `json:"user,omitempty"`
+	SortValue *int  `json:"sort_value,omitempty"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/sticker#sticker-object-sticker-types
+const (
+	StickerTypeStandard = 1
+	StickerTypeGuild    = 2
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/sticker#sticker-object-sticker-format-types
+const (
+	StickerFormatPng    = 1
+	StickerFormatApng   = 2
+	StickerFormatLottie = 3
+	StickerFormatGif    = 4
+)
+
+// Reference: https://discordapp.com/developers/docs/resources/sticker#list-guild-stickers
+func (client *DiscordClient) ListGuildStickers(guildId Snowflake) (stickers []Sticker, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/stickers", guildsEndpoint, guildId),
+		result:   &stickers,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/sticker#get-guild-sticker
+func (client *DiscordClient) GetGuildSticker(guildId Snowflake, stickerId Snowflake) (sticker Sticker, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s/stickers/%s", guildsEndpoint, guildId, stickerId),
+		result:   &sticker,
+	})
+	return
+}
+
+// Sticker files are limited to 512 KiB.
+const maxStickerSize = 512 * 1024
+
+// Reference: https://discordapp.com/developers/docs/resources/sticker#create-guild-sticker-form-params
+type StickerOptions struct {
+	// 2-30 characters.
+	Name string
+	// Empty or 2-100 characters.
+	Description string
+	// Name of the unicode emoji the sticker relates to, used for autocomplete.
+	Tags string
+}
+
+// Uploads a sticker from a PNG, APNG, GIF or Lottie JSON file of up to 512 KiB.
+// Reference: https://discordapp.com/developers/docs/resources/sticker#create-guild-sticker
+func (client *DiscordClient) CreateGuildSticker(
+	guildId Snowflake, options StickerOptions, file io.Reader, reason string,
+) (sticker Sticker, err error) {
+
+	data, err := readLimited(file, maxStickerSize)
+
+	if err != nil {
+		return sticker, fmt.Errorf("invalid sticker file: %v", err)
+	}
+
+	contentType, extension := stickerFileType(data)
+	if contentType == "" {
+		return sticker, fmt.Errorf("invalid sticker file: unsupported type [%s]", http.DetectContentType(data))
+	}
+
+	body := bytes.Buffer{}
+	form := multipart.NewWriter(&body)
+
+	for _, field := range [][2]string{
+		{"name", options.Name}, {"description", options.Description}, {"tags", options.Tags},
+	} {
+		if err = form.WriteField(field[0], field[1]); err != nil {
+			return
+		}
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="sticker`+extension+`"`)
+	header.Set("Content-Type", contentType)
+
+	var part io.Writer
+	if part, err = form.CreatePart(header); err == nil {
+		_, err = part.Write(data)
+	}
+
+	if err == nil {
+		err = form.Close()
+	}
+
+	if err != nil {
+		return sticker, fmt.Errorf("failed to build sticker upload: %v", err)
+	}
+
+	err = client.do(context.Background(), restRequest{
+		method:      http.MethodPost,
+		endpoint:    fmt.Sprintf("%s/%s/stickers", guildsEndpoint, guildId),
+		data:        body.Bytes(),
+		contentType: form.FormDataContentType(),
+		reason:      reason,
+		result:      &sticker,
+	})
+	return
+}
+
+// Detects the content type and file extension of a sticker file, or returns an empty type if the
+// file isn't a supported format.
+func stickerFileType(data []byte) (contentType string, extension string) {
+	switch detected := http.DetectContentType(data); {
+	case detected == "image/png":
+		return detected, ".png"
+	case detected == "image/gif":
+		return detected, ".gif"
+	case strings.HasPrefix(detected, "text/plain") && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")):
+		// Lottie animations are JSON.
+		return "application/json", ".json"
+	}
+	return "", ""
+}
+
+// Changes to a sticker.
+// Reference: https://discordapp.com/developers/docs/resources/sticker#modify-guild-sticker-json-params
+type StickerPatch struct {
+	fields jsonPatch
+}
+
+func (p *StickerPatch) SetName(name string) *StickerPatch {
+	p.fields.set("name", name)
+	return p
+}
+
+// Nil removes the description.
+func (p *StickerPatch) SetDescription(description *string) *StickerPatch {
+	p.fields.set("description", description)
+	return p
+}
+
+func (p *StickerPatch) SetTags(tags string) *StickerPatch {
+	p.fields.set("tags", tags)
+	return p
+}
+
+func (p StickerPatch) MarshalJSON() ([]byte, error) {
+	return p.fields.marshal()
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/sticker#modify-guild-sticker
+func (client *DiscordClient) ModifyGuildSticker(
+	guildId Snowflake, stickerId Snowflake, patch StickerPatch, reason string,
+) (sticker Sticker, err error) {
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: fmt.Sprintf("%s/%s/stickers/%s", guildsEndpoint, guildId, stickerId),
+		body:     patch,
+		reason:   reason,
+		result:   &sticker,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/sticker#delete-guild-sticker
+func (client *DiscordClient) DeleteGuildSticker(guildId Snowflake, stickerId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/%s/stickers/%s", guildsEndpoint, guildId, stickerId),
+		reason:   reason,
+	})
+}
@@ -0,0 +1,56 @@
+package discordbot
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ISO8601 timestamp as sent by Discord, e.g. "2017-07-11T17:27:07.299000+00:00". Fractional seconds
+// are optional. A zero timestamp is serialized as null.
+// Reference: https://discordapp.com/developers/docs/reference#iso8601-datetime
+type Timestamp struct {
+	time.Time
+}
+
+// Discord sends microsecond precision when there are fractional seconds.
+const timestampFormat = "2006-01-02T15:04:05-07:00"
+const timestampFractionalFormat = "2006-01-02T15:04:05.000000-07:00"
+
+func (t Timestamp) String() string {
+	if t.Nanosecond() == 0 {
+		return t.Format(timestampFormat)
+	}
+	return t.Format(timestampFractionalFormat)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(t.String())), nil
+}
+
+// Null leaves the timestamp unchanged.
+func (t *Timestamp) UnmarshalJSON(data []byte) (err error) {
+	if bytes.Equal(data, []byte("null")) {
+		return
+	}
+
+	var text string
+	text, err = strconv.Unquote(string(data))
+
+	if err != nil {
+		return fmt.Errorf("invalid timestamp [%s]: %v", data, err)
+	}
+
+	// Also accepts timestamps without fractional seconds.
+	t.Time, err = time.Parse(time.RFC3339Nano, text)
+
+	if err != nil {
+		return fmt.Errorf("invalid timestamp [%s]: %v", text, err)
+	}
+
+	return
+}
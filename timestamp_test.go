@@ -0,0 +1,53 @@
+package discordbot_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestTimestampRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		`"2017-07-11T17:27:07.299000+00:00"`,
+		`"2017-07-11T17:27:07+00:00"`,
+		`"2017-07-11T19:27:07.000001+02:00"`,
+	} {
+		timestamp := discordbot.Timestamp{}
+		if err := json.Unmarshal([]byte(text), &timestamp); err != nil {
+			t.Fatal(err)
+		}
+
+		encoded, err := json.Marshal(timestamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(encoded) != text {
+			t.Errorf("expected %s, got %s", text, encoded)
+		}
+	}
+}
+
+func TestTimestampMessageFields(t *testing.T) {
+	message := discordbot.Message{}
+	err := json.Unmarshal(
+		[]byte(`{"timestamp":"2017-07-11T17:27:07.299000+00:00","edited_timestamp":null}`), &message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := time.Date(2017, time.July, 11, 17, 27, 7, 299*int(time.Millisecond), time.UTC)
+	if !message.Timestamp.Equal(expected) {
+		t.Errorf("expected [%v], got [%v]", expected, message.Timestamp)
+	}
+
+	if message.EditedTimestamp != nil {
+		t.Errorf("expected null edited timestamp, got [%v]", message.EditedTimestamp)
+	}
+
+	if err := json.Unmarshal([]byte(`{"timestamp":"yesterday"}`), &message); err == nil {
+		t.Error("expected error decoding invalid timestamp")
+	}
+}
@@ -1,14 +1,336 @@
 package discordbot
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
 // Reference:
 // https://discordapp.com/developers/docs/resources/user#user-object-user-structure
 type User struct {
-	Id            string  `json:"id"`
-	Username      string  `json:"username"`
-	Discriminator string  `json:"discriminator"`
-	Avatar        *string `json:"avatar"`
-	Bot           *bool   `json:"bot"`
-	MfaEnabled    *bool   `json:"mfa_enabled"`
-	Verified      *bool   `json:"verified"`
-	Email         *string `json:"email"`
+	Id Snowflake `json:"id"`
+	// Not unique for users that haven't picked a new username.
+	Username string `json:"username"`
+	// "0" for users with a new, unique username.
+	Discriminator string `json:"discriminator"`
+	// Display name, if set, shown instead of the username.
+	GlobalName  *string `json:"global_name"`
+	Avatar      *string `json:"avatar"`
+	Banner      *string `json:"banner"`
+	AccentColor *Color  `json:"accent_color"`
+	Bot         *bool   `json:"bot"`
+	// Official Discord system user.
+	System      *bool        `json:"system"`
+	MfaEnabled  *bool        `json:"mfa_enabled"`
+	Locale      *string      `json:"locale"`
+	Verified    *bool        `json:"verified"`
+	Email       *string      `json:"email"`
+	Flags       *UserFlags   `json:"flags"`
+	PremiumType *PremiumType `json:"premium_type"`
+	PublicFlags *UserFlags   `json:"public_flags"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/user#user-object-user-flags
+type UserFlags int
+
+const (
+	UserFlagStaff                 UserFlags = 1 << 0
+	UserFlagPartner               UserFlags = 1 << 1
+	UserFlagHypesquad             UserFlags = 1 << 2
+	UserFlagBugHunterLevel1       UserFlags = 1 << 3
+	UserFlagHypesquadBravery      UserFlags = 1 << 6
+	UserFlagHypesquadBrilliance   UserFlags = 1 << 7
+	UserFlagHypesquadBalance      UserFlags = 1 << 8
+	UserFlagPremiumEarlySupporter UserFlags = 1 << 9
+	UserFlagTeamUser              UserFlags = 1 << 10
+	UserFlagBugHunterLevel2       UserFlags = 1 << 14
+	UserFlagVerifiedBot           UserFlags = 1 << 16
+	UserFlagVerifiedDeveloper     UserFlags = 1 << 17
+	UserFlagCertifiedModerator    UserFlags = 1 << 18
+	// Bot that only receives interactions over HTTP.
+	UserFlagBotHttpInteractions UserFlags = 1 << 19
+	UserFlagActiveDeveloper     UserFlags = 1 << 22
+)
+
+// Whether all of the given flags are set.
+func (f UserFlags) Has(flags UserFlags) bool {
+	return f&flags == flags
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/user#user-object-premium-types
+type PremiumType int
+
+const (
+	PremiumTypeNone         PremiumType = 0
+	PremiumTypeNitroClassic PremiumType = 1
+	PremiumTypeNitro        PremiumType = 2
+	PremiumTypeNitroBasic   PremiumType = 3
+)
+
+// Text that mentions the user in a message.
+func (u User) Mention() string {
+	return "<@" + u.Id.String() + ">"
+}
+
+// The username, followed by #discriminator for users that haven't picked a new username.
+func (u User) Tag() string {
+	if u.hasNewUsername() {
+		return u.Username
+	}
+	return u.Username + "#" + u.Discriminator
+}
+
+func (u User) hasNewUsername() bool {
+	return u.Discriminator == "" || u.Discriminator == "0"
+}
+
+// URL of the user's avatar, or of the default avatar if none is set. Size is a power of 2 from
+// 16 to 4096, or 0 for the original size.
+func (u User) AvatarUrl(size int, format ImageFormat) string {
+	if u.Avatar == nil {
+		return u.DefaultAvatarUrl()
+	}
+	return cdnImageUrl(fmt.Sprintf("/avatars/%s", u.Id), *u.Avatar, size, format)
+}
+
+// URL of the avatar shown for users that haven't set one, which is always a PNG.
+// Reference: https://discordapp.com/developers/docs/reference#image-formatting-cdn-endpoints
+func (u User) DefaultAvatarUrl() string {
+	var index uint64
+	if u.hasNewUsername() {
+		index = uint64(u.Id>>22) % 6
+	} else {
+		discriminator, _ := strconv.Atoi(u.Discriminator)
+		index = uint64(discriminator % 5)
+	}
+	return fmt.Sprintf("%s/embed/avatars/%d.png", cdnUrl, index)
+}
+
+// URL of the user's profile banner at its original size, as a GIF if animated and a PNG otherwise.
+// Empty if the user has no banner or it wasn't sent; banners are only sent when getting a single user.
+func (u User) BannerUrl() string {
+	if u.Banner == nil {
+		return ""
+	}
+	return cdnImageUrl(fmt.Sprintf("/banners/%s", u.Id), *u.Banner, 0, ImageFormatDefault)
+}
+
+const usersEndpoint = "/users"
+
+// Reference: https://discordapp.com/developers/docs/resources/user#get-current-user
+func (client *DiscordClient) GetCurrentUser() (user User, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: usersEndpoint + "/@me",
+		result:   &user,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/user#get-user
+func (client *DiscordClient) GetUser(userId Snowflake) (user User, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: fmt.Sprintf("%s/%s", usersEndpoint, userId),
+		result:   &user,
+	})
+	return
+}
+
+// Avatars are limited to 10 MiB.
+const maxAvatarSize = 10 * 1024 * 1024
+
+// Changes to the current user. Zero fields are left unchanged.
+type CurrentUserUpdate struct {
+	// Changing the username may also change the discriminator.
+	Username string
+	// PNG, JPEG or GIF image.
+	Avatar io.Reader
+	// Resets to the default avatar. Ignored if Avatar is set.
+	RemoveAvatar bool
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/user#modify-current-user
+func (client *DiscordClient) ModifyCurrentUser(update CurrentUserUpdate, reason string) (user User, err error) {
+	patch := jsonPatch{}
+
+	if update.Username != "" {
+		patch.set("username", update.Username)
+	}
+
+	if update.Avatar != nil {
+		var avatar string
+		avatar, err = imageDataUri(update.Avatar, maxAvatarSize, imageDataTypes)
+
+		if err != nil {
+			return user, fmt.Errorf("invalid avatar: %v", err)
+		}
+		patch.set("avatar", avatar)
+	} else if update.RemoveAvatar {
+		patch.set("avatar", nil)
+	}
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPatch,
+		endpoint: usersEndpoint + "/@me",
+		body:     patch,
+		reason:   reason,
+		result:   &user,
+	})
+	return
+}
+
+// Page of the current user's guilds. Zero fields are unset.
+type CurrentUserGuildsQuery struct {
+	// Only guilds with a smaller ID.
+	Before Snowflake
+	// Only guilds with a greater ID. Set to the last guild of the previous page to get the next one.
+	After Snowflake
+	// 1-200, defaults to 200.
+	Limit int
+}
+
+// Gets a page of partial guilds the current user is in, in ID order. Only the ID, name, icon,
+// owner, permissions and features are set.
+// Reference: https://discordapp.com/developers/docs/resources/user#get-current-user-guilds
+func (client *DiscordClient) GetCurrentUserGuilds(query CurrentUserGuildsQuery) (guilds []Guild, err error) {
+	values := url.Values{}
+	if query.Before != 0 {
+		values.Set("before", query.Before.String())
+	}
+	if query.After != 0 {
+		values.Set("after", query.After.String())
+	}
+	if query.Limit != 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodGet,
+		endpoint: usersEndpoint + "/@me/guilds",
+		query:    values,
+		result:   &guilds,
+	})
+	return
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/user#leave-guild
+func (client *DiscordClient) LeaveGuild(guildId Snowflake, reason string) error {
+	return client.do(context.Background(), restRequest{
+		method:   http.MethodDelete,
+		endpoint: fmt.Sprintf("%s/@me/guilds/%s", usersEndpoint, guildId),
+		reason:   reason,
+	})
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/user#create-dm-json-params
+type createDm struct {
+	RecipientId Snowflake `json:"recipient_id"`
+}
+
+// Opens the DM channel with a user, returning the existing one if there is one.
+// Reference: https://discordapp.com/developers/docs/resources/user#create-dm
+func (client *DiscordClient) CreateDM(recipientId Snowflake, reason string) (channel Channel, err error) {
+	err = client.do(context.Background(), restRequest{
+		method:   http.MethodPost,
+		endpoint: usersEndpoint + "/@me/channels",
+		body:     createDm{RecipientId: recipientId},
+		reason:   reason,
+		result:   &channel,
+	})
+	return
+}
+
+// Number of DM channel IDs each client keeps.
+const maxCachedDmChannels = 1000
+
+// DM channel IDs by recipient. When full, an arbitrary channel is forgotten to make room, which
+// only costs reopening it. A nil cache holds nothing.
+type dmChannelCache struct {
+	mutex      sync.Mutex
+	channelIds map[Snowflake]Snowflake
+}
+
+func newDmChannelCache() *dmChannelCache {
+	return &dmChannelCache{channelIds: make(map[Snowflake]Snowflake)}
+}
+
+func (c *dmChannelCache) get(userId Snowflake) (channelId Snowflake, ok bool) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	channelId, ok = c.channelIds[userId]
+	return
+}
+
+func (c *dmChannelCache) put(userId Snowflake, channelId Snowflake) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.channelIds[userId]; !ok && len(c.channelIds) >= maxCachedDmChannels {
+		for cachedUserId := range c.channelIds {
+			delete(c.channelIds, cachedUserId)
+			break
+		}
+	}
+	c.channelIds[userId] = channelId
+}
+
+func (c *dmChannelCache) remove(userId Snowflake) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.channelIds, userId)
+}
+
+// Discord's JSON error code for a channel that doesn't exist.
+// Reference: https://discordapp.com/developers/docs/topics/opcodes-and-status-codes#json-json-error-codes
+const restErrorUnknownChannel = 10003
+
+// Sends a message to a user, opening the DM channel on first use. Clients from NewDiscordClient
+// cache the channel ID so that later messages to the same user only need one request.
+func (client *DiscordClient) SendDirectMessage(
+	userId Snowflake, message OutgoingMessage, reason string,
+) (sentMessage Message, err error) {
+
+	channelId, ok := client.dmChannels.get(userId)
+
+	if !ok {
+		var channel Channel
+		channel, err = client.CreateDM(userId, reason)
+
+		if err != nil {
+			return sentMessage, fmt.Errorf("failed to open DM channel: %w", err)
+		}
+
+		channelId = channel.Id
+		client.dmChannels.put(userId, channelId)
+	}
+
+	sentMessage, err = client.SendMessage(channelId, message, reason)
+
+	// Forget channels that no longer exist so that the next message opens a new one.
+	restErr := &RestError{}
+	if errors.As(err, &restErr) && restErr.Code == restErrorUnknownChannel {
+		client.dmChannels.remove(userId)
+	}
+	return
 }
@@ -0,0 +1,158 @@
+package discordbot_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestModifyCurrentUser(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"id": "1", "username": "bot", "discriminator": "0001"}
+	})
+
+	client := api.client(t)
+	user, err := client.ModifyCurrentUser(discordbot.CurrentUserUpdate{Avatar: bytes.NewReader(pngImage)}, "new look")
+
+	if err != nil || user.Username != "bot" {
+		t.Fatalf("unexpected user %+v: %v", user, err)
+	}
+
+	request := api.single(t)
+	body := map[string]interface{}{}
+	json.Unmarshal(request.Body, &body)
+
+	avatar, _ := body["avatar"].(string)
+	if request.Method != http.MethodPatch || request.Path != "/users/@me" || len(body) != 1 ||
+		!strings.HasPrefix(avatar, "data:image/png;base64,") {
+
+		t.Errorf("unexpected request %s %s %s", request.Method, request.Path, request.Body)
+	}
+	if reason := request.Header.Get("X-Audit-Log-Reason"); reason != "new%20look" {
+		t.Errorf("unexpected audit log reason [%s]", reason)
+	}
+}
+
+func TestGetCurrentUserGuilds(t *testing.T) {
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		return http.StatusOK, []interface{}{map[string]interface{}{"id": "6", "name": "Guild", "owner": true}}
+	})
+
+	client := api.client(t)
+	guilds, err := client.GetCurrentUserGuilds(discordbot.CurrentUserGuildsQuery{After: 5, Limit: 1})
+
+	if err != nil || len(guilds) != 1 || guilds[0].Id != 6 {
+		t.Fatalf("unexpected guilds %+v: %v", guilds, err)
+	}
+
+	if request := api.single(t); request.Path != "/users/@me/guilds" || request.Query != "after=5&limit=1" {
+		t.Errorf("unexpected request %s?%s", request.Path, request.Query)
+	}
+}
+
+func TestSendDirectMessageCachesChannel(t *testing.T) {
+	deleted := false
+	api := newFakeApi(t, func(request fakeApiRequest) (int, interface{}) {
+		switch {
+		case request.Path == "/users/@me/channels":
+			return http.StatusOK, map[string]interface{}{"id": "10", "type": 1}
+		case deleted:
+			return http.StatusNotFound, map[string]interface{}{"code": 10003, "message": "Unknown Channel"}
+		default:
+			return http.StatusOK, map[string]interface{}{"id": "11", "channel_id": "10", "content": "hi"}
+		}
+	})
+
+	// Copies of a client, such as one embedded in a gateway, share its DM channels.
+	client := api.client(t)
+	copied := client
+	for _, sender := range []*discordbot.DiscordClient{&client, &copied} {
+		message, err := sender.SendDirectMessage(2, discordbot.OutgoingMessage{Content: "hi"}, "")
+
+		if err != nil || message.ChannelId != 10 {
+			t.Fatalf("unexpected message %+v: %v", message, err)
+		}
+	}
+
+	paths := []string{}
+	for _, request := range api.received() {
+		paths = append(paths, request.Path)
+	}
+
+	if strings.Join(paths, " ") != "/users/@me/channels /channels/10/messages /channels/10/messages" {
+		t.Errorf("expected the DM channel to be opened once, got %v", paths)
+	}
+
+	deleted = true
+	if _, err := client.SendDirectMessage(2, discordbot.OutgoingMessage{Content: "hi"}, ""); err == nil {
+		t.Fatal("expected an error for a deleted channel")
+	}
+	deleted = false
+
+	client.SendDirectMessage(2, discordbot.OutgoingMessage{Content: "hi"}, "")
+
+	if requests := api.received(); requests[len(requests)-2].Path != "/users/@me/channels" {
+		t.Error("expected the DM channel to be reopened after it was deleted")
+	}
+}
+
+func TestUserAvatarUrl(t *testing.T) {
+	hash := "a_1269e74af4df7417b13759eae50c83dc"
+	user := discordbot.User{Id: 80351110224678912, Username: "Nelly", Discriminator: "1337", Avatar: &hash}
+
+	tests := []struct {
+		size   int
+		format discordbot.ImageFormat
+		want   string
+	}{
+		{0, discordbot.ImageFormatDefault, "https://cdn.discordapp.com/avatars/80351110224678912/" + hash + ".gif"},
+		{128, discordbot.ImageFormatWebp, "https://cdn.discordapp.com/avatars/80351110224678912/" + hash + ".webp?size=128"},
+	}
+
+	for _, test := range tests {
+		if url := user.AvatarUrl(test.size, test.format); url != test.want {
+			t.Errorf("expected %s, got %s", test.want, url)
+		}
+	}
+
+	static := "1269e74af4df7417b13759eae50c83dc"
+	user.Avatar = &static
+	if url := user.AvatarUrl(0, discordbot.ImageFormatGif); !strings.HasSuffix(url, static+".png") {
+		t.Errorf("expected a PNG for a static avatar, got %s", url)
+	}
+
+	user.Avatar = nil
+	if url := user.AvatarUrl(0, discordbot.ImageFormatDefault); url != "https://cdn.discordapp.com/embed/avatars/2.png" {
+		t.Errorf("expected the default avatar for discriminator 1337, got %s", url)
+	}
+
+	user.Discriminator = "0"
+	if url := user.DefaultAvatarUrl(); url != "https://cdn.discordapp.com/embed/avatars/5.png" {
+		t.Errorf("expected the default avatar for a new username, got %s", url)
+	}
+
+	if url := user.BannerUrl(); url != "" {
+		t.Errorf("expected no banner, got %s", url)
+	}
+
+	user.Banner = &hash
+	if url := user.BannerUrl(); url != "https://cdn.discordapp.com/banners/80351110224678912/"+hash+".gif" {
+		t.Errorf("unexpected banner %s", url)
+	}
+}
+
+func TestUserTag(t *testing.T) {
+	user := discordbot.User{Id: 1, Username: "Nelly", Discriminator: "1337"}
+	if user.Tag() != "Nelly#1337" || user.Mention() != "<@1>" {
+		t.Errorf("unexpected tag %s or mention %s", user.Tag(), user.Mention())
+	}
+
+	user.Discriminator = "0"
+	if user.Tag() != "Nelly" {
+		t.Errorf("expected no discriminator for a new username, got %s", user.Tag())
+	}
+}
@@ -0,0 +1,247 @@
+package discordbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Reference:
+// https://discordapp.com/developers/docs/resources/voice#voice-state-object-voice-state-structure
+type VoiceState struct {
+	GuildId   *Snowflake   `json:"guild_id,omitempty"`
+	ChannelId *Snowflake   `json:"channel_id"`
+	UserId    Snowflake    `json:"user_id"`
+	Member    *GuildMember `json:"member,omitempty"`
+	SessionId string       `json:"session_id"`
+	Deaf      bool         `json:"deaf"`
+	Mute      bool         `json:"mute"`
+	SelfDeaf  bool         `json:"self_deaf"`
+	SelfMute  bool         `json:"self_mute"`
+	Suppress  bool         `json:"suppress"`
+}
+
+// Reference: https://discordapp.com/developers/docs/resources/voice#voice-region-object-voice-region-structure
+type VoiceRegion struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Vip  bool   `json:"vip"`
+	// Closest to the current user's client.
+	Optimal    bool `json:"optimal"`
+	Deprecated bool `json:"deprecated"`
+	// Only available to custom events.
+	Custom bool `json:"custom"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#voice-server-update-voice-server-update-event-fields
+type VoiceServerUpdate struct {
+	Token   string    `json:"token"`
+	GuildId Snowflake `json:"guild_id"`
+	// Null when the voice server is unavailable, in which case another update follows.
+	Endpoint *string `json:"endpoint"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/gateway#update-voice-state-gateway-voice-state-update-structure
+type gatewayVoiceStateUpdate struct {
+	GuildId Snowflake `json:"guild_id"`
+	// Null to disconnect.
+	ChannelId *Snowflake `json:"channel_id"`
+	SelfMute  bool       `json:"self_mute"`
+	SelfDeaf  bool       `json:"self_deaf"`
+}
+
+// Details needed to open a connection to a voice server, gathered from the
+// VOICE_STATE_UPDATE and VOICE_SERVER_UPDATE events sent after joining a channel.
+type VoiceConnectionInfo struct {
+	GuildId   Snowflake
+	ChannelId Snowflake
+	UserId    Snowflake
+	SessionId string
+	Token     string
+	Endpoint  string
+}
+
+// Voice channel joins waiting on their state and server updates, keyed by guild ID.
+type voiceJoinRequests struct {
+	mutex   sync.Mutex
+	pending map[Snowflake]*voiceJoinRequest
+}
+
+type voiceJoinRequest struct {
+	userId  Snowflake
+	states  chan VoiceState
+	servers chan VoiceServerUpdate
+	// Closed once the requester stops waiting.
+	done chan struct{}
+}
+
+// How long to wait for the voice events if the context has no earlier deadline.
+const joinVoiceTimeout = time.Duration(10) * time.Second
+
+func newVoiceJoinRequests() *voiceJoinRequests {
+	return &voiceJoinRequests{pending: make(map[Snowflake]*voiceJoinRequest)}
+}
+
+func (r *voiceJoinRequests) add(guildId Snowflake, userId Snowflake) (request *voiceJoinRequest, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.pending[guildId]; ok {
+		return nil, fmt.Errorf("already joining a voice channel in guild [%s]", guildId)
+	}
+
+	request = &voiceJoinRequest{
+		userId:  userId,
+		states:  make(chan VoiceState),
+		servers: make(chan VoiceServerUpdate),
+		done:    make(chan struct{}),
+	}
+	r.pending[guildId] = request
+	return
+}
+
+func (r *voiceJoinRequests) remove(guildId Snowflake) {
+	r.mutex.Lock()
+	if request, ok := r.pending[guildId]; ok {
+		close(request.done)
+		delete(r.pending, guildId)
+	}
+	r.mutex.Unlock()
+}
+
+func (r *voiceJoinRequests) get(guildId Snowflake) (request *voiceJoinRequest, ok bool) {
+	r.mutex.Lock()
+	request, ok = r.pending[guildId]
+	r.mutex.Unlock()
+	return
+}
+
+// Called on VOICE_STATE_UPDATE. Forwards our own state to the pending join for its guild.
+func (r *voiceJoinRequests) stateRecv(payload GatewayPayload) {
+	state := VoiceState{}
+	if err := json.Unmarshal(payload.EventData, &state); err != nil {
+		log.Print("Failed to parse voice state update: ", err)
+		return
+	}
+
+	if state.GuildId == nil {
+		return
+	}
+
+	request, ok := r.get(*state.GuildId)
+	if !ok || state.UserId != request.userId {
+		return
+	}
+
+	select {
+	case request.states <- state:
+	case <-request.done:
+	}
+}
+
+// Called on VOICE_SERVER_UPDATE. Forwards the server to the pending join for its guild.
+func (r *voiceJoinRequests) serverRecv(payload GatewayPayload) {
+	server := VoiceServerUpdate{}
+	if err := json.Unmarshal(payload.EventData, &server); err != nil {
+		log.Print("Failed to parse voice server update: ", err)
+		return
+	}
+
+	request, ok := r.get(server.GuildId)
+	if !ok {
+		return
+	}
+
+	select {
+	case request.servers <- server:
+	case <-request.done:
+	}
+}
+
+func (g *DiscordGateway) sendVoiceStateUpdate(update gatewayVoiceStateUpdate) (err error) {
+	var updateJsonBytes json.RawMessage
+	updateJsonBytes, err = json.Marshal(&update)
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal voice state update: %v", err)
+	}
+
+	err = g.SendPayload(&GatewayPayload{
+		Opcode:    OpcodeVoiceStateUpdate,
+		EventData: updateJsonBytes,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to send voice state update: %v", err)
+	}
+
+	return
+}
+
+// Joins or moves to a voice channel and waits for the details needed to connect to its voice server.
+// Must be called after Identify.
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#retrieving-voice-server-information
+func (g *DiscordGateway) JoinVoice(
+	ctx context.Context, guildId Snowflake, channelId Snowflake, mute bool, deaf bool,
+) (info VoiceConnectionInfo, err error) {
+
+	userId, ok := g.session.getUserId()
+
+	if !ok {
+		return info, fmt.Errorf("cannot join voice before identify")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, joinVoiceTimeout)
+	defer cancel()
+
+	var request *voiceJoinRequest
+	request, err = g.voiceJoins.add(guildId, userId)
+
+	if err != nil {
+		return
+	}
+	defer g.voiceJoins.remove(guildId)
+
+	err = g.sendVoiceStateUpdate(gatewayVoiceStateUpdate{
+		GuildId:   guildId,
+		ChannelId: &channelId,
+		SelfMute:  mute,
+		SelfDeaf:  deaf,
+	})
+
+	if err != nil {
+		return
+	}
+
+	info.GuildId = guildId
+	info.ChannelId = channelId
+	info.UserId = request.userId
+
+	// The two events may arrive in either order.
+	for info.SessionId == "" || info.Endpoint == "" {
+		select {
+		case state := <-request.states:
+			info.SessionId = state.SessionId
+		case server := <-request.servers:
+			if server.Endpoint != nil {
+				info.Token = server.Token
+				info.Endpoint = *server.Endpoint
+			}
+		case <-ctx.Done():
+			return info, fmt.Errorf("did not receive voice server details before timeout: %v", ctx.Err())
+		}
+	}
+
+	return
+}
+
+// Leaves the current voice channel in a guild.
+func (g *DiscordGateway) LeaveVoice(guildId Snowflake) (err error) {
+	return g.sendVoiceStateUpdate(gatewayVoiceStateUpdate{
+		GuildId:   guildId,
+		ChannelId: nil,
+	})
+}
@@ -0,0 +1,90 @@
+package discordbot_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+)
+
+func TestJoinVoice(t *testing.T) {
+	fake := newFakeGateway(t)
+	gateway := fake.connect(t)
+	fake.identify(t, gateway)
+
+	type result struct {
+		info discordbot.VoiceConnectionInfo
+		err  error
+	}
+	done := make(chan result)
+	go func() {
+		info, err := gateway.JoinVoice(context.Background(), 41771983423143937, 127121515262115840, false, true)
+		done <- result{info, err}
+	}()
+
+	payload := fake.expect(t, discordbot.OpcodeVoiceStateUpdate)
+	request := map[string]interface{}{}
+	if err := json.Unmarshal(payload.EventData, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if request["channel_id"] != "127121515262115840" || request["self_deaf"] != true {
+		t.Fatalf("unexpected voice state update: %s", payload.EventData)
+	}
+
+	// Another user's state in the same guild must not be used.
+	fake.dispatch(t, discordbot.EventVoiceStateUpdate, map[string]interface{}{
+		"guild_id": "41771983423143937", "channel_id": "127121515262115840", "user_id": "1", "session_id": "wrong",
+	})
+	fake.dispatch(t, discordbot.EventVoiceServerUpdate, map[string]interface{}{
+		"guild_id": "41771983423143937", "token": "voice-token", "endpoint": "voice.example.com:443",
+	})
+	fake.dispatch(t, discordbot.EventVoiceStateUpdate, map[string]interface{}{
+		"guild_id": "41771983423143937", "channel_id": "127121515262115840", "user_id": fakeUserId, "session_id": "voice-session",
+	})
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+
+		expected := discordbot.VoiceConnectionInfo{
+			GuildId:   41771983423143937,
+			ChannelId: 127121515262115840,
+			UserId:    fakeUserId,
+			SessionId: "voice-session",
+			Token:     "voice-token",
+			Endpoint:  "voice.example.com:443",
+		}
+		if r.info != expected {
+			t.Errorf("expected %+v, got %+v", expected, r.info)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("join did not complete")
+	}
+
+	if err := gateway.LeaveVoice(41771983423143937); err != nil {
+		t.Fatal(err)
+	}
+
+	payload = fake.expect(t, discordbot.OpcodeVoiceStateUpdate)
+	request = map[string]interface{}{}
+	if err := json.Unmarshal(payload.EventData, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if channelId, ok := request["channel_id"]; !ok || channelId != nil {
+		t.Errorf("expected null channel on leave, got %s", payload.EventData)
+	}
+}
+
+func TestLeaveVoiceBeforeConnect(t *testing.T) {
+	gateway := &discordbot.DiscordGateway{}
+
+	if err := gateway.LeaveVoice(1); err == nil {
+		t.Error("expected an error when not connected")
+	}
+}
@@ -0,0 +1,421 @@
+package discordbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Voice opcode constants.
+// https://discordapp.com/developers/docs/topics/opcodes-and-status-codes#voice-voice-opcodes
+const (
+	// Send: used to begin a voice websocket connection
+	VoiceOpcodeIdentify = 0
+	// Send: used to select the voice protocol
+	VoiceOpcodeSelectProtocol = 1
+	// Receive: used to complete the websocket handshake
+	VoiceOpcodeReady = 2
+	// Send: used to keep the websocket connection alive
+	VoiceOpcodeHeartbeat = 3
+	// Receive: used to describe the session
+	VoiceOpcodeSessionDescription = 4
+	// Send/Receive: used to indicate which users are speaking
+	VoiceOpcodeSpeaking = 5
+	// Receive: used to acknowledge a received client heartbeat
+	VoiceOpcodeHeartbeatACK = 6
+	// Send: used to resume a connection
+	VoiceOpcodeResume = 7
+	// Receive: used to determine heartbeat interval
+	VoiceOpcodeHello = 8
+	// Receive: used to acknowledge a successful session resume
+	VoiceOpcodeResumed = 9
+	// Receive: a client has disconnected from the voice channel
+	VoiceOpcodeClientDisconnect = 13
+)
+
+// https://discordapp.com/developers/docs/topics/voice-connections#voice-gateway-versioning-gateway-versions
+const voiceGatewayVersion = 3
+
+// The only encryption mode supported for sending audio.
+const voiceEncryptionMode = "xsalsa20_poly1305"
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#establishing-a-voice-websocket-connection
+type VoicePayload struct {
+	Opcode int             `json:"op"`
+	Data   json.RawMessage `json:"d"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#establishing-a-voice-websocket-connection-example-voice-identify-payload
+type voiceIdentifyRequest struct {
+	ServerId  Snowflake `json:"server_id"`
+	UserId    Snowflake `json:"user_id"`
+	SessionId string    `json:"session_id"`
+	Token     string    `json:"token"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#establishing-a-voice-websocket-connection-example-voice-ready-payload
+type voiceReadyResponse struct {
+	Ssrc  uint32   `json:"ssrc"`
+	Ip    string   `json:"ip"`
+	Port  int      `json:"port"`
+	Modes []string `json:"modes"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#heartbeating-example-hello-payload-since-v3
+type voiceHelloResponse struct {
+	HeartbeatInterval float64 `json:"heartbeat_interval"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#establishing-a-voice-udp-connection-example-select-protocol-payload
+type voiceSelectProtocolRequest struct {
+	Protocol string                  `json:"protocol"`
+	Data     voiceSelectProtocolData `json:"data"`
+}
+
+type voiceSelectProtocolData struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	Mode    string `json:"mode"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#establishing-a-voice-udp-connection-example-session-description-payload
+type voiceSessionDescription struct {
+	Mode      string   `json:"mode"`
+	SecretKey [32]byte `json:"secret_key"`
+}
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#speaking-example-speaking-payload
+type voiceSpeaking struct {
+	Speaking int    `json:"speaking"`
+	Delay    int    `json:"delay"`
+	Ssrc     uint32 `json:"ssrc"`
+}
+
+// A connection to a voice server, made up of a voice websocket for signalling and a UDP socket for audio.
+type VoiceConnection struct {
+	Info      VoiceConnectionInfo
+	conn      *websocket.Conn
+	connMutex *sync.Mutex
+	heartbeat *discordHeartbeat
+	udp       *voiceUdpConn
+
+	// Handshake payloads are forwarded here by the read loop until setupDone is closed.
+	setup     chan VoicePayload
+	setupDone chan struct{}
+	// Closed when the read loop exits.
+	closed chan struct{}
+}
+
+// How long to wait for each step of the voice handshake.
+const voiceHandshakeTimeout = time.Duration(10) * time.Second
+
+// Builds the voice websocket URL for an endpoint. Endpoints are usually a bare host, optionally
+// with the legacy port 80, but may also include a ws:// or wss:// scheme.
+func voiceGatewayUrl(endpoint string) string {
+	if !strings.HasPrefix(endpoint, "ws://") && !strings.HasPrefix(endpoint, "wss://") {
+		endpoint = "wss://" + strings.TrimSuffix(endpoint, ":80")
+	}
+	return endpoint + fmt.Sprintf("/?v=%d", voiceGatewayVersion)
+}
+
+// Connects to the voice server described by info (see DiscordGateway.JoinVoice), performs the
+// websocket handshake and UDP IP discovery, and negotiates the encryption key for sending audio.
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections
+func ConnectVoice(info VoiceConnectionInfo) (voice *VoiceConnection, err error) {
+	voice = &VoiceConnection{
+		Info:      info,
+		connMutex: new(sync.Mutex),
+		setup:     make(chan VoicePayload),
+		setupDone: make(chan struct{}),
+		closed:    make(chan struct{}),
+	}
+	voice.heartbeat = newDiscordHeartbeat(voice, new(heartbeatLatency))
+
+	dialer := websocket.Dialer{}
+	voice.conn, _, err = dialer.Dial(voiceGatewayUrl(info.Endpoint), nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial voice gateway: %v", err)
+	}
+
+	go voice.readLoop()
+
+	err = voice.handshake()
+
+	if err != nil {
+		voice.Close()
+		return nil, err
+	}
+
+	return voice, nil
+}
+
+func (v *VoiceConnection) handshake() (err error) {
+	// Lets the read loop know nothing else is waiting on setup payloads.
+	defer close(v.setupDone)
+
+	err = v.sendVoicePayload(VoiceOpcodeIdentify, voiceIdentifyRequest{
+		ServerId:  v.Info.GuildId,
+		UserId:    v.Info.UserId,
+		SessionId: v.Info.SessionId,
+		Token:     v.Info.Token,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to send voice identify: %v", err)
+	}
+
+	// Hello and ready may arrive in either order.
+	var hello *voiceHelloResponse
+	var ready *voiceReadyResponse
+	for hello == nil || ready == nil {
+		var payload VoicePayload
+		payload, err = v.awaitSetup()
+
+		if err != nil {
+			return
+		}
+
+		switch payload.Opcode {
+		case VoiceOpcodeHello:
+			hello = new(voiceHelloResponse)
+			err = json.Unmarshal(payload.Data, hello)
+		case VoiceOpcodeReady:
+			ready = new(voiceReadyResponse)
+			err = json.Unmarshal(payload.Data, ready)
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to parse voice payload [%s]: %v", payload.Data, err)
+		}
+	}
+
+	heartbeatInterval := time.Duration(hello.HeartbeatInterval * float64(time.Millisecond))
+
+	if heartbeatInterval <= 0 {
+		return fmt.Errorf("invalid heartbeat interval [%v] in voice hello", heartbeatInterval)
+	}
+
+	startHeartbeat(v.heartbeat, heartbeatInterval)
+
+	v.udp, err = dialVoiceUdp(ready.Ip, ready.Port, ready.Ssrc)
+
+	if err != nil {
+		return
+	}
+
+	var address string
+	var port int
+	address, port, err = v.udp.discoverIp()
+
+	if err != nil {
+		return
+	}
+
+	err = v.sendVoicePayload(VoiceOpcodeSelectProtocol, voiceSelectProtocolRequest{
+		Protocol: "udp",
+		Data: voiceSelectProtocolData{
+			Address: address,
+			Port:    port,
+			Mode:    voiceEncryptionMode,
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to send select protocol: %v", err)
+	}
+
+	for {
+		var payload VoicePayload
+		payload, err = v.awaitSetup()
+
+		if err != nil {
+			return
+		}
+
+		if payload.Opcode != VoiceOpcodeSessionDescription {
+			continue
+		}
+
+		description := voiceSessionDescription{}
+		err = json.Unmarshal(payload.Data, &description)
+
+		if err != nil {
+			return fmt.Errorf("unable to parse session description [%s]: %v", payload.Data, err)
+		}
+
+		if description.Mode != voiceEncryptionMode {
+			return fmt.Errorf("unsupported voice encryption mode [%s]", description.Mode)
+		}
+
+		v.udp.secretKey = description.SecretKey
+		return
+	}
+}
+
+// Waits for the next handshake payload from the read loop.
+func (v *VoiceConnection) awaitSetup() (payload VoicePayload, err error) {
+	select {
+	case payload = <-v.setup:
+	case <-v.closed:
+		err = fmt.Errorf("voice connection closed during handshake")
+	case <-time.After(voiceHandshakeTimeout):
+		err = fmt.Errorf("voice handshake timed out")
+	}
+	return
+}
+
+func (v *VoiceConnection) readLoop() {
+	defer close(v.closed)
+	setup := v.setup
+
+	for {
+		payload := VoicePayload{}
+		err := v.conn.ReadJSON(&payload)
+
+		if err != nil {
+			log.Print("Voice connection read ended: ", err)
+			return
+		}
+
+		log.Printf("Received voice payload with Opcode [%v] and data [%s].", payload.Opcode, payload.Data)
+
+		switch payload.Opcode {
+		case VoiceOpcodeHeartbeatACK:
+			v.heartbeat.acknowledge()
+		case VoiceOpcodeHello, VoiceOpcodeReady, VoiceOpcodeSessionDescription:
+			if setup == nil {
+				continue
+			}
+
+			select {
+			case setup <- payload:
+			case <-v.setupDone:
+				setup = nil
+			}
+		}
+	}
+}
+
+func (v *VoiceConnection) sendVoicePayload(opcode int, data interface{}) (err error) {
+	var dataJsonBytes json.RawMessage
+	dataJsonBytes, err = json.Marshal(data)
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal voice payload: %v", err)
+	}
+
+	v.connMutex.Lock()
+
+	log.Printf("Sending voice payload with Opcode [%v] and data [%s].", opcode, dataJsonBytes)
+	err = v.conn.WriteJSON(&VoicePayload{Opcode: opcode, Data: dataJsonBytes})
+
+	v.connMutex.Unlock()
+	return
+}
+
+func (v *VoiceConnection) SendControl(messageType int, data []byte, deadline time.Time) (err error) {
+	v.connMutex.Lock()
+
+	err = v.conn.WriteControl(messageType, data, deadline)
+
+	v.connMutex.Unlock()
+	return
+}
+
+// Voice heartbeats carry a nonce that the server echoes in the ack.
+func (v *VoiceConnection) sendHeartbeat() error {
+	return v.sendVoicePayload(VoiceOpcodeHeartbeat, time.Now().UnixNano()/int64(time.Millisecond))
+}
+
+// The voice server has stopped responding, so the connection can't be used any more.
+func (v *VoiceConnection) heartbeatFailed(err error) {
+	log.Print("Closing voice connection: ", err)
+	v.Close()
+}
+
+// Returns the round trip time of the last acknowledged voice heartbeat, and a smoothed average
+// over recent heartbeats. Both are zero until the first ack is received.
+func (v *VoiceConnection) Latency() (last time.Duration, smoothed time.Duration) {
+	return v.heartbeat.latency.get()
+}
+
+// Sets the speaking indicator. Must be set before sending audio.
+func (v *VoiceConnection) Speaking(speaking bool) (err error) {
+	flag := 0
+	if speaking {
+		flag = 1
+	}
+
+	return v.sendVoicePayload(VoiceOpcodeSpeaking, voiceSpeaking{
+		Speaking: flag,
+		Ssrc:     v.udp.ssrc,
+	})
+}
+
+// Sends a single 20ms Opus frame, encrypted, to the voice server.
+func (v *VoiceConnection) SendOpusFrame(frame []byte) (err error) {
+	return v.udp.sendOpusFrame(frame)
+}
+
+// Plays Opus frames from the channel at the 20ms frame rate until it is closed, followed by the
+// silence frames Discord expects at the end of a transmission.
+func (v *VoiceConnection) PlayOpus(frames <-chan []byte) (err error) {
+	err = v.Speaking(true)
+
+	if err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(opusFrameDuration)
+	defer ticker.Stop()
+
+	for frame := range frames {
+		<-ticker.C
+		err = v.SendOpusFrame(frame)
+
+		if err != nil {
+			return
+		}
+	}
+
+	for i := 0; i < opusSilenceFrameCount; i++ {
+		<-ticker.C
+		err = v.SendOpusFrame(opusSilenceFrame)
+
+		if err != nil {
+			return
+		}
+	}
+
+	return v.Speaking(false)
+}
+
+// Closes the voice websocket and UDP connection. Use DiscordGateway.LeaveVoice to leave the channel.
+func (v *VoiceConnection) Close() (err error) {
+	stopHeartbeat(v.heartbeat)
+
+	v.SendControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(closeTimeoutSeconds),
+	)
+	err = v.conn.Close()
+
+	if v.udp != nil {
+		v.udp.conn.Close()
+	}
+
+	return
+}
+
+// The local UDP address used for audio, for use by callers that need to match packets to a connection.
+func (v *VoiceConnection) LocalAddr() net.Addr {
+	return v.udp.conn.LocalAddr()
+}
@@ -0,0 +1,209 @@
+package discordbot_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdewald/discordbot"
+	"github.com/gorilla/websocket"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const fakeVoiceSsrc = 12345
+
+var fakeVoiceKey = [32]byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+// Local stand-in for a Discord voice server: a voice websocket plus a UDP peer that answers
+// IP discovery and records audio packets.
+type fakeVoiceServer struct {
+	server   *httptest.Server
+	udp      *net.UDPConn
+	received chan discordbot.VoicePayload
+	packets  chan []byte
+}
+
+func newFakeVoiceServer(t *testing.T) *fakeVoiceServer {
+	fake := &fakeVoiceServer{
+		received: make(chan discordbot.VoicePayload, 100),
+		packets:  make(chan []byte, 100),
+	}
+
+	var err error
+	fake.udp, err = net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { fake.udp.Close() })
+	go fake.serveUdp()
+
+	upgrader := websocket.Upgrader{}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		for {
+			payload := discordbot.VoicePayload{}
+			if err := conn.ReadJSON(&payload); err != nil {
+				return
+			}
+			fake.received <- payload
+
+			switch payload.Opcode {
+			case discordbot.VoiceOpcodeIdentify:
+				udpAddr := fake.udp.LocalAddr().(*net.UDPAddr)
+				writeVoicePayload(t, conn, discordbot.VoiceOpcodeReady, map[string]interface{}{
+					"ssrc": fakeVoiceSsrc, "ip": "127.0.0.1", "port": udpAddr.Port, "modes": []string{"xsalsa20_poly1305"},
+				})
+				writeVoicePayload(t, conn, discordbot.VoiceOpcodeHello, map[string]interface{}{
+					"heartbeat_interval": 50.0,
+				})
+			case discordbot.VoiceOpcodeSelectProtocol:
+				writeVoicePayload(t, conn, discordbot.VoiceOpcodeSessionDescription, map[string]interface{}{
+					"mode": "xsalsa20_poly1305", "secret_key": fakeVoiceKey,
+				})
+			case discordbot.VoiceOpcodeHeartbeat:
+				writeVoicePayload(t, conn, discordbot.VoiceOpcodeHeartbeatACK, payload.Data)
+			}
+		}
+	}))
+	t.Cleanup(fake.server.Close)
+
+	return fake
+}
+
+func writeVoicePayload(t *testing.T, conn *websocket.Conn, opcode int, data interface{}) {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.WriteJSON(discordbot.VoicePayload{Opcode: opcode, Data: dataBytes})
+}
+
+func (f *fakeVoiceServer) serveUdp() {
+	buffer := make([]byte, 1500)
+	for {
+		n, addr, err := f.udp.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+
+		packet := append([]byte(nil), buffer[:n]...)
+		if n == 74 && binary.BigEndian.Uint16(packet[0:2]) == 0x1 {
+			response := make([]byte, 74)
+			binary.BigEndian.PutUint16(response[0:2], 0x2)
+			binary.BigEndian.PutUint16(response[2:4], 70)
+			copy(response[4:8], packet[4:8])
+			copy(response[8:72], addr.IP.String())
+			binary.BigEndian.PutUint16(response[72:74], uint16(addr.Port))
+			f.udp.WriteToUDP(response, addr)
+			continue
+		}
+
+		f.packets <- packet
+	}
+}
+
+func (f *fakeVoiceServer) expect(t *testing.T, opcode int) discordbot.VoicePayload {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case payload := <-f.received:
+			if payload.Opcode == opcode {
+				return payload
+			}
+		case <-timeout:
+			t.Fatalf("did not receive voice opcode [%d]", opcode)
+		}
+	}
+}
+
+func TestConnectVoiceAndSendOpus(t *testing.T) {
+	fake := newFakeVoiceServer(t)
+
+	voice, err := discordbot.ConnectVoice(discordbot.VoiceConnectionInfo{
+		GuildId:   41771983423143937,
+		ChannelId: 127121515262115840,
+		UserId:    80351110224678912,
+		SessionId: "voice-session",
+		Token:     "voice-token",
+		Endpoint:  "ws" + strings.TrimPrefix(fake.server.URL, "http"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer voice.Close()
+
+	identify := map[string]string{}
+	if err := json.Unmarshal(fake.expect(t, discordbot.VoiceOpcodeIdentify).Data, &identify); err != nil {
+		t.Fatal(err)
+	}
+	if identify["server_id"] != "41771983423143937" || identify["token"] != "voice-token" {
+		t.Errorf("unexpected identify: %+v", identify)
+	}
+
+	selectProtocol := struct {
+		Protocol string
+		Data     struct {
+			Address string
+			Port    int
+			Mode    string
+		}
+	}{}
+	if err := json.Unmarshal(fake.expect(t, discordbot.VoiceOpcodeSelectProtocol).Data, &selectProtocol); err != nil {
+		t.Fatal(err)
+	}
+
+	localAddr := voice.LocalAddr().(*net.UDPAddr)
+	if selectProtocol.Data.Address != "127.0.0.1" || selectProtocol.Data.Port != localAddr.Port {
+		t.Errorf("select protocol did not use discovered address: %+v", selectProtocol)
+	}
+
+	fake.expect(t, discordbot.VoiceOpcodeHeartbeat)
+
+	frames := [][]byte{{0xAA, 0xBB}, {0xCC}}
+	for _, frame := range frames {
+		if err := voice.SendOpusFrame(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, frame := range frames {
+		var packet []byte
+		select {
+		case packet = <-fake.packets:
+		case <-time.After(5 * time.Second):
+			t.Fatal("did not receive voice packet")
+		}
+
+		header := packet[:12]
+		if header[0] != 0x80 || header[1] != 0x78 {
+			t.Errorf("invalid RTP header %x", header)
+		}
+		if sequence := binary.BigEndian.Uint16(header[2:4]); sequence != uint16(i) {
+			t.Errorf("expected sequence [%d], got [%d]", i, sequence)
+		}
+		if timestamp := binary.BigEndian.Uint32(header[4:8]); timestamp != uint32(i*960) {
+			t.Errorf("expected timestamp [%d], got [%d]", i*960, timestamp)
+		}
+		if ssrc := binary.BigEndian.Uint32(header[8:12]); ssrc != fakeVoiceSsrc {
+			t.Errorf("expected ssrc [%d], got [%d]", fakeVoiceSsrc, ssrc)
+		}
+
+		var nonce [24]byte
+		copy(nonce[:], header)
+		opened, ok := secretbox.Open(nil, packet[12:], &nonce, &fakeVoiceKey)
+		if !ok || string(opened) != string(frame) {
+			t.Errorf("expected frame %x, decrypted %x (ok: %v)", frame, opened, ok)
+		}
+	}
+}
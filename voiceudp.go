@@ -0,0 +1,128 @@
+package discordbot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// Discord audio is 48kHz stereo Opus in 20ms frames.
+const opusFrameDuration = time.Duration(20) * time.Millisecond
+const opusSamplesPerFrame = 960
+
+// Sent after audio stops to avoid interpolation artifacts.
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#voice-data-interpolation
+var opusSilenceFrame = []byte{0xF8, 0xFF, 0xFE}
+
+const opusSilenceFrameCount = 5
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#encrypting-and-sending-voice
+const rtpHeaderSize = 12
+const rtpVersionFlags = 0x80
+const rtpPayloadType = 0x78
+
+// Reference: https://discordapp.com/developers/docs/topics/voice-connections#ip-discovery
+const ipDiscoveryPacketSize = 74
+const ipDiscoveryRequestType = 0x1
+const ipDiscoveryResponseType = 0x2
+const ipDiscoveryTimeout = time.Duration(5) * time.Second
+
+// UDP socket that encrypted audio is sent over.
+type voiceUdpConn struct {
+	conn      *net.UDPConn
+	ssrc      uint32
+	secretKey [32]byte
+
+	// Guards the RTP sequence and timestamp.
+	mutex     sync.Mutex
+	sequence  uint16
+	timestamp uint32
+}
+
+func dialVoiceUdp(ip string, port int, ssrc uint32) (udp *voiceUdpConn, err error) {
+	var addr *net.UDPAddr
+	addr, err = net.ResolveUDPAddr("udp", net.JoinHostPort(ip, strconv.Itoa(port)))
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid voice server address: %v", err)
+	}
+
+	udp = &voiceUdpConn{ssrc: ssrc}
+	udp.conn, err = net.DialUDP("udp", nil, addr)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial voice server: %v", err)
+	}
+
+	return
+}
+
+// Asks the voice server for our external address and port, which it needs for select protocol.
+func (u *voiceUdpConn) discoverIp() (address string, port int, err error) {
+	request := make([]byte, ipDiscoveryPacketSize)
+	binary.BigEndian.PutUint16(request[0:2], ipDiscoveryRequestType)
+	binary.BigEndian.PutUint16(request[2:4], ipDiscoveryPacketSize-4)
+	binary.BigEndian.PutUint32(request[4:8], u.ssrc)
+
+	_, err = u.conn.Write(request)
+
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to send ip discovery: %v", err)
+	}
+
+	response := make([]byte, ipDiscoveryPacketSize)
+	u.conn.SetReadDeadline(time.Now().Add(ipDiscoveryTimeout))
+	defer u.conn.SetReadDeadline(time.Time{})
+
+	var n int
+	n, err = u.conn.Read(response)
+
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to receive ip discovery: %v", err)
+	}
+
+	if n != ipDiscoveryPacketSize || binary.BigEndian.Uint16(response[0:2]) != ipDiscoveryResponseType {
+		return "", 0, fmt.Errorf("invalid ip discovery response [%x]", response[:n])
+	}
+
+	// Address is null terminated.
+	addressBytes := response[8:72]
+	if end := bytes.IndexByte(addressBytes, 0); end >= 0 {
+		addressBytes = addressBytes[:end]
+	}
+
+	return string(addressBytes), int(binary.BigEndian.Uint16(response[72:74])), nil
+}
+
+// Wraps the frame in an RTP header, encrypts it and sends it.
+func (u *voiceUdpConn) sendOpusFrame(frame []byte) (err error) {
+	u.mutex.Lock()
+	header := make([]byte, rtpHeaderSize)
+	header[0] = rtpVersionFlags
+	header[1] = rtpPayloadType
+	binary.BigEndian.PutUint16(header[2:4], u.sequence)
+	binary.BigEndian.PutUint32(header[4:8], u.timestamp)
+	binary.BigEndian.PutUint32(header[8:12], u.ssrc)
+	u.sequence++
+	u.timestamp += opusSamplesPerFrame
+	u.mutex.Unlock()
+
+	// The nonce is the RTP header padded with zeros.
+	var nonce [24]byte
+	copy(nonce[:], header)
+
+	packet := secretbox.Seal(header, frame, &nonce, &u.secretKey)
+	_, err = u.conn.Write(packet)
+
+	if err != nil {
+		return fmt.Errorf("failed to send voice packet: %v", err)
+	}
+
+	return
+}